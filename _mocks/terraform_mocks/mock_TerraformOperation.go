@@ -0,0 +1,78 @@
+package terraform_mocks
+
+import (
+	"context"
+
+	"terraform-provider-tfmigrate/internal/terraform"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTerraformOperation is a mock implementation of the terraform.TerraformOperationInterface interface.
+type MockTerraformOperation struct {
+	mock.Mock
+}
+
+// ExecuteTerraformPlan mocks the ExecuteTerraformPlan method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) ExecuteTerraformPlan(ctx context.Context) (*terraform.TerraformPlanSummary, error) {
+	args := m.Called(ctx)
+	//handle the case where the first argument is nil
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*terraform.TerraformPlanSummary), args.Error(1)
+}
+
+// ExecuteTerraformInit mocks the ExecuteTerraformInit method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) ExecuteTerraformInit(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// SelectWorkspace mocks the SelectWorkspace method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) SelectWorkspace(ctx context.Context, workspace string) error {
+	args := m.Called(ctx, workspace)
+	return args.Error(0)
+}
+
+// StatePull mocks the StatePull method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) StatePull(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	//handle the case where the first argument is nil
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// StatePullWorkspace mocks the StatePullWorkspace method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) StatePullWorkspace(ctx context.Context, workspace string) ([]byte, error) {
+	args := m.Called(ctx, workspace)
+	//handle the case where the first argument is nil
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// StateMv mocks the StateMv method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) StateMv(ctx context.Context, source string, destination string) error {
+	args := m.Called(ctx, source, destination)
+	return args.Error(0)
+}
+
+// StatePush mocks the StatePush method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) StatePush(ctx context.Context, path string) error {
+	args := m.Called(ctx, path)
+	return args.Error(0)
+}
+
+// Validate mocks the Validate method of the terraform.TerraformOperationInterface interface.
+func (m *MockTerraformOperation) Validate(ctx context.Context) ([]terraform.TerraformValidateDiagnostic, error) {
+	args := m.Called(ctx)
+	//handle the case where the first argument is nil
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]terraform.TerraformValidateDiagnostic), args.Error(1)
+}
@@ -434,7 +434,7 @@ func (_c *MockGitUtil_GetGitToken_Call) RunAndReturn(run func(*constants.GitServ
 }
 
 // GetOrgAndRepoName provides a mock function with given fields: repoIdentifier
-func (_m *MockGitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string) {
+func (_m *MockGitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string, error) {
 	ret := _m.Called(repoIdentifier)
 
 	if len(ret) == 0 {
@@ -443,7 +443,8 @@ func (_m *MockGitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string)
 
 	var r0 string
 	var r1 string
-	if rf, ok := ret.Get(0).(func(string) (string, string)); ok {
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (string, string, error)); ok {
 		return rf(repoIdentifier)
 	}
 	if rf, ok := ret.Get(0).(func(string) string); ok {
@@ -458,7 +459,13 @@ func (_m *MockGitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string)
 		r1 = ret.Get(1).(string)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(repoIdentifier)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // MockGitUtil_GetOrgAndRepoName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrgAndRepoName'
@@ -479,12 +486,12 @@ func (_c *MockGitUtil_GetOrgAndRepoName_Call) Run(run func(repoIdentifier string
 	return _c
 }
 
-func (_c *MockGitUtil_GetOrgAndRepoName_Call) Return(_a0 string, _a1 string) *MockGitUtil_GetOrgAndRepoName_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockGitUtil_GetOrgAndRepoName_Call) Return(_a0 string, _a1 string, _a2 error) *MockGitUtil_GetOrgAndRepoName_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockGitUtil_GetOrgAndRepoName_Call) RunAndReturn(run func(string) (string, string)) *MockGitUtil_GetOrgAndRepoName_Call {
+func (_c *MockGitUtil_GetOrgAndRepoName_Call) RunAndReturn(run func(string) (string, string, error)) *MockGitUtil_GetOrgAndRepoName_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -77,6 +77,62 @@ func (_c *MockGitlabSvcProvider_CreatePullRequest_Call) RunAndReturn(run func(gi
 	return _c
 }
 
+// GetDefaultBranch provides a mock function with given fields: repoIdentifier
+func (_m *MockGitlabSvcProvider) GetDefaultBranch(repoIdentifier string) (string, error) {
+	ret := _m.Called(repoIdentifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDefaultBranch")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(repoIdentifier)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(repoIdentifier)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(repoIdentifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGitlabSvcProvider_GetDefaultBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaultBranch'
+type MockGitlabSvcProvider_GetDefaultBranch_Call struct {
+	*mock.Call
+}
+
+// GetDefaultBranch is a helper method to define mock.On call
+//   - repoIdentifier string
+func (_e *MockGitlabSvcProvider_Expecter) GetDefaultBranch(repoIdentifier interface{}) *MockGitlabSvcProvider_GetDefaultBranch_Call {
+	return &MockGitlabSvcProvider_GetDefaultBranch_Call{Call: _e.mock.On("GetDefaultBranch", repoIdentifier)}
+}
+
+func (_c *MockGitlabSvcProvider_GetDefaultBranch_Call) Run(run func(repoIdentifier string)) *MockGitlabSvcProvider_GetDefaultBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGitlabSvcProvider_GetDefaultBranch_Call) Return(_a0 string, _a1 error) *MockGitlabSvcProvider_GetDefaultBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGitlabSvcProvider_GetDefaultBranch_Call) RunAndReturn(run func(string) (string, error)) *MockGitlabSvcProvider_GetDefaultBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ValidateToken provides a mock function with given fields: repoUrl, repoIdentifier
 func (_m *MockGitlabSvcProvider) ValidateToken(repoUrl string, repoIdentifier string) (string, error) {
 	ret := _m.Called(repoUrl, repoIdentifier)
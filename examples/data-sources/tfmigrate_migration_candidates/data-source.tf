@@ -0,0 +1,14 @@
+data "tfmigrate_migration_candidates" "aws" {
+  org = "example-org"
+  filters = [
+    {
+      field    = "providers"
+      operator = "contains"
+      value    = "hashicorp/aws"
+    }
+  ]
+}
+
+output "first_candidate" {
+  value = data.tfmigrate_migration_candidates.aws.candidates[0].workspace_name
+}
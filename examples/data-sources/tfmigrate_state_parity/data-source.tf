@@ -0,0 +1,10 @@
+data "tfmigrate_state_parity" "audit" {
+  directory_path  = "/Users/example/terraform/directory"
+  local_workspace = "default"
+  org             = "example-org"
+  tfc_workspace   = "default"
+}
+
+output "missing_addresses" {
+  value = data.tfmigrate_state_parity.audit.missing_addresses
+}
@@ -0,0 +1,5 @@
+data "tfmigrate_tfe_api" "current" {}
+
+output "tfe_api_base_url" {
+  value = data.tfmigrate_tfe_api.current.base_url
+}
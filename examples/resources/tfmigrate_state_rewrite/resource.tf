@@ -0,0 +1,8 @@
+resource "tfmigrate_state_rewrite" "state-rewrite" {
+  directory_path = "/Users/example/terraform/directory"
+  workspace      = "default"
+  address_map = {
+    "aws_instance.web"   = "module.web.aws_instance.this"
+    "module.network_old" = "module.network"
+  }
+}
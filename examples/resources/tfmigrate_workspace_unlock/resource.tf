@@ -0,0 +1,4 @@
+resource "tfmigrate_workspace_unlock" "workspace-unlock" {
+  org        = "example-org"
+  workspaces = ["app-frontend", "app-backend"]
+}
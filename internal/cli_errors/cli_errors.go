@@ -16,9 +16,13 @@ var (
 	ErrTfGitPatTokenValid              = GitTokenError(`TF_GIT_PAT_TOKEN is valid`)
 	ErrTfGitPatTokenInvalid            = GitTokenError(`TF_GIT_PAT_TOKEN is invalid`)
 
-	ErrServerError          = ApiError(`server error during API call`)
-	ErrUnexpectedStatusCode = ApiError(`unexpected API status code`)
-	ErrUnknownError         = ApiError(`unknown error occurred during API call`)
+	ErrRemoteUrlInvalid      = GitRemoteUrlError(`the git remote URL could not be parsed`)
+	ErrRepoIdentifierInvalid = GitRemoteUrlError(`the repository identifier must be in the format "owner/repo"`)
+
+	ErrDefaultBranchNotFound = ApiError(`the default branch of the repository could not be determined`)
+	ErrServerError           = ApiError(`server error during API call`)
+	ErrUnexpectedStatusCode  = ApiError(`unexpected API status code`)
+	ErrUnknownError          = ApiError(`unknown error occurred during API call`)
 )
 
 // CliOperationError represents the type of error that occurred during the CLI operation.
@@ -35,6 +39,13 @@ func (e GitTokenError) Error() string {
 	return string(e)
 }
 
+// GitRemoteUrlError represents errors while parsing a git remote URL.
+type GitRemoteUrlError string
+
+func (e GitRemoteUrlError) Error() string {
+	return string(e)
+}
+
 // ApiError represents the type of error that occurred during the API call.
 type ApiError string
 
@@ -13,6 +13,7 @@ import (
 	"terraform-provider-tfmigrate/internal/util/vcs/git/remote_svc_provider"
 	"time"
 
+	cliErrs "terraform-provider-tfmigrate/internal/cli_errors"
 	consts "terraform-provider-tfmigrate/internal/constants"
 	gitUtil "terraform-provider-tfmigrate/internal/util/vcs/git"
 
@@ -24,8 +25,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var err error
-
 type gitOperations struct {
 	ctx     context.Context
 	gitUtil gitUtil.GitUtil
@@ -40,6 +39,7 @@ type GitOperations interface {
 	CreateCommit(repoPath, message string) (string, error)
 	PushCommit(repoPath string, remoteName string, branchName string, githubToken string, force bool) error
 	CreatePullRequest(params gitUtil.PullRequestParams) (string, error)
+	GetDefaultBranch(repoIdentifier string) (string, error)
 	PushCommitUsingGit(remoteName string, branchName string) error
 	GetRepoIdentifier(repoUrl string) string
 	GetRemoteServiceProvider(remoteURL string) *consts.GitServiceProvider
@@ -73,33 +73,47 @@ func NewGitOperations(ctx context.Context, gitUtil gitUtil.GitUtil) GitOperation
 }
 
 // GetRemoteName returns the remote name.
+// If the repository has several remotes, origin is preferred, otherwise the first remote by name is returned.
 func (gitOps *gitOperations) GetRemoteName() (string, error) {
-	// run git remote command to get the remote name.
-	cmd := exec.Command("git", "remote")
-	out, err := cmd.Output()
+	repo, err := gitOps.gitUtil.OpenRepository(".")
 	if err != nil {
-		errorMessage := fmt.Sprintf("error getting remote name: %s", string(out))
-		return gitOps.logAndReturnErr(errorMessage, err)
+		return gitOps.logAndReturnErr("error getting remote name", err)
 	}
 
-	// get the remote name from the output.
-	remoteName := strings.TrimSpace(string(out))
-	if remoteName == "" {
+	remotes, err := gitOps.gitUtil.Remotes(repo)
+	if err != nil {
+		return gitOps.logAndReturnErr("error getting remote name", err)
+	}
+	if len(remotes) == 0 {
 		return "", errors.New(strings.ToLower(consts.ErrNoRemoteSet))
 	}
 
-	return remoteName, nil
+	remoteNames := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		remoteNames = append(remoteNames, remote.Config().Name)
+	}
+	if slices.Contains(remoteNames, git.DefaultRemoteName) {
+		return git.DefaultRemoteName, nil
+	}
+	slices.Sort(remoteNames)
+	return remoteNames[0], nil
 }
 
 // GetRemoteURL returns the remote URL.
 func (gitOps *gitOperations) GetRemoteURL(remoteName string) (string, error) {
-	cmd := exec.Command("git", "remote", "get-url", remoteName)
-	out, err := cmd.Output()
+	repo, err := gitOps.gitUtil.OpenRepository(".")
 	if err != nil {
-		errorMessage := fmt.Sprintf("error getting remote url: %s", string(out))
-		return gitOps.logAndReturnErr(errorMessage, err)
+		return gitOps.logAndReturnErr("error getting remote url", err)
+	}
+
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return gitOps.logAndReturnErr("error getting remote url", err)
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
 	}
-	return strings.TrimSpace(string(out)), nil
+	return "", fmt.Errorf("remote %s has no url", remoteName)
 }
 
 // ResetToLastCommittedVersion resets the workspace to last commit version.
@@ -294,8 +308,8 @@ func (gitOps *gitOperations) CreatePullRequest(pullRequestParams gitUtil.PullReq
 		return "", fmt.Errorf(consts.InvalidRepositoryIdentifier, pullRequestParams.RepoIdentifier)
 	}
 
-	var remoteServiceProvider *consts.GitServiceProvider
-	if remoteServiceProvider, err = gitOps.getVcsProviderName(); err != nil {
+	remoteServiceProvider, err := gitOps.getVcsProviderName()
+	if err != nil {
 		return "", err
 	}
 
@@ -311,6 +325,50 @@ func (gitOps *gitOperations) CreatePullRequest(pullRequestParams gitUtil.PullReq
 	return prUrl, nil
 }
 
+// GetDefaultBranch returns the default branch of the remote repository.
+// The branch is looked up through the GitHub or GitLab API. If that fails, the remote HEAD recorded
+// in the local repository when it was cloned is used instead.
+func (gitOps *gitOperations) GetDefaultBranch(repoIdentifier string) (string, error) {
+	remoteServiceProvider, err := gitOps.getVcsProviderName()
+	if err != nil {
+		return "", err
+	}
+
+	remoteVcsSvcProvider, err := remote_svc_provider.NewRemoteSvcProviderFactory(gitOps.ctx).NewRemoteVcsSvcProvider(remoteServiceProvider)
+	if err == nil {
+		defaultBranch, apiErr := remoteVcsSvcProvider.GetDefaultBranch(repoIdentifier)
+		if apiErr == nil {
+			return defaultBranch, nil
+		}
+		err = apiErr
+	}
+	tflog.Warn(gitOps.ctx, "Failed to get the default branch from the API, falling back to the remote HEAD", map[string]interface{}{"error": err})
+
+	remoteName, err := gitOps.GetRemoteName()
+	if err != nil {
+		return "", err
+	}
+	return gitOps.getDefaultBranchFromRemoteHead(".", remoteName)
+}
+
+// getDefaultBranchFromRemoteHead returns the branch that refs/remotes/<remoteName>/HEAD points to.
+func (gitOps *gitOperations) getDefaultBranchFromRemoteHead(repoPath string, remoteName string) (string, error) {
+	repo, err := gitOps.gitUtil.OpenRepository(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	remoteHead, err := repo.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false)
+	if err != nil {
+		tflog.Error(gitOps.ctx, "Failed to read the remote HEAD", map[string]interface{}{"remote": remoteName, "error": err})
+		return "", fmt.Errorf("%w: %s", cliErrs.ErrDefaultBranchNotFound, err)
+	}
+	if remoteHead.Type() != plumbing.SymbolicReference || !remoteHead.Target().IsRemote() {
+		return "", cliErrs.ErrDefaultBranchNotFound
+	}
+	return strings.TrimPrefix(remoteHead.Target().Short(), remoteName+"/"), nil
+}
+
 // GetRepoIdentifier returns the repository identifier.
 // This is a wrapper around the GetRepoIdentifier method in GitUtil.
 // This is done to avoid direct dependency on GitUtil in the client code.
@@ -16,6 +16,7 @@ import (
 	gitUtil "terraform-provider-tfmigrate/internal/util/vcs/git"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -31,24 +32,48 @@ var (
 )
 
 func TestGitRemoteName(t *testing.T) {
-	t.Skip("Skipping test as it requires a git repository need to spend some time with docker remote repo setup")
 	for name, tc := range map[string]struct {
+		remotes    []string
 		remoteName string
 		error      error
 	}{
 		"has valid remote name": {
-			"origin",
-			nil,
+			remotes:    []string{"origin"},
+			remoteName: "origin",
+		},
+		"prefers origin over other remotes": {
+			remotes:    []string{"fork", "origin", "upstream"},
+			remoteName: "origin",
+		},
+		"first remote by name without origin": {
+			remotes:    []string{"upstream", "fork"},
+			remoteName: "fork",
+		},
+		"no remote set": {
+			error: errors.New(strings.ToLower(consts.ErrNoRemoteSet)),
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			// Arrange
 			r := require.New(t)
-			ctx := context.Background()
-			gitInterface := NewGitOperations(ctx, gitUtil.NewGitUtil(ctx))
-			remoteName, err := gitInterface.GetRemoteName()
-			if err != nil {
-				r.Equal(tc.error, err)
+			mockGitUtil := git_mocks.NewMockGitUtil(t)
+			repo, err := git.PlainInit(t.TempDir(), false)
+			r.NoError(err)
+			var remotes []*git.Remote
+			for _, remoteName := range tc.remotes {
+				remote, err := repo.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{"git@github.com:hashicorp/tf-migrate.git"}})
+				r.NoError(err)
+				remotes = append(remotes, remote)
 			}
+			mockGitUtil.On("OpenRepository", ".").Return(repo, nil)
+			mockGitUtil.On("Remotes", repo).Return(remotes, nil)
+			gitInterface := NewGitOperations(ctx, mockGitUtil)
+
+			// Act
+			remoteName, err := gitInterface.GetRemoteName()
+
+			// Assert
+			r.Equal(tc.error, err)
 			r.Equal(tc.remoteName, remoteName)
 		})
 	}
@@ -57,30 +82,40 @@ func TestGitRemoteName(t *testing.T) {
 func TestGetRemoteURL(t *testing.T) {
 
 	for name, tc := range map[string]struct {
-		url   string
-		error error
+		remoteName string
+		url        string
+		error      string
 	}{
 		"has valid repo url": {
-			"git@github.com:hashicorp/terraform-provider-tfmigrate.git",
-			nil,
+			remoteName: "origin",
+			url:        "git@github.com:hashicorp/terraform-provider-tfmigrate.git",
+		},
+		"remote not found": {
+			remoteName: "upstream",
+			error:      "remote not found",
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			// Arrange
 			r := require.New(t)
-			ctx := context.Background()
-			gitInterface := NewGitOperations(ctx, gitUtil.NewGitUtil(ctx))
-			repoUrl, err := gitInterface.GetRemoteURL("origin")
-			if err != nil {
-				r.Equal(tc.error, err)
-			}
-			t.Logf("Repo URL: %s", repoUrl)
+			mockGitUtil := git_mocks.NewMockGitUtil(t)
+			repo, err := git.PlainInit(t.TempDir(), false)
+			r.NoError(err)
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"git@github.com:hashicorp/terraform-provider-tfmigrate.git"}})
+			r.NoError(err)
+			mockGitUtil.On("OpenRepository", ".").Return(repo, nil)
+			gitInterface := NewGitOperations(ctx, mockGitUtil)
 
-			if strings.Contains(repoUrl, "https://") {
-				tc.url = "https://github.com/hashicorp/terraform-provider-tfmigrate"
-			}
+			// Act
+			repoUrl, err := gitInterface.GetRemoteURL(tc.remoteName)
 
+			// Assert
+			if tc.error != "" {
+				r.ErrorContains(err, tc.error)
+				return
+			}
+			r.NoError(err)
 			r.Equal(tc.url, repoUrl)
-
 		})
 	}
 }
@@ -766,3 +801,51 @@ func TestCreatePullRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDefaultBranchFromRemoteHead(t *testing.T) {
+	for name, tc := range map[string]struct {
+		remoteHead  *plumbing.Reference
+		expected    string
+		expectedErr string
+	}{
+		"remoteHeadPointsToMain": {
+			remoteHead: plumbing.NewSymbolicReference(plumbing.NewRemoteHEADReferenceName("origin"), plumbing.NewRemoteReferenceName("origin", "main")),
+			expected:   "main",
+		},
+		"remoteHeadPointsToBranchWithSlash": {
+			remoteHead: plumbing.NewSymbolicReference(plumbing.NewRemoteHEADReferenceName("origin"), plumbing.NewRemoteReferenceName("origin", "release/v1")),
+			expected:   "release/v1",
+		},
+		"remoteHeadNotSet": {
+			expectedErr: "the default branch of the repository could not be determined",
+		},
+		"remoteHeadNotSymbolic": {
+			remoteHead:  plumbing.NewHashReference(plumbing.NewRemoteHEADReferenceName("origin"), plumbing.ZeroHash),
+			expectedErr: "the default branch of the repository could not be determined",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			mockGitUtil := git_mocks.NewMockGitUtil(t)
+			repo, err := git.PlainInit(t.TempDir(), false)
+			r.NoError(err)
+			if tc.remoteHead != nil {
+				r.NoError(repo.Storer.SetReference(tc.remoteHead))
+			}
+			mockGitUtil.On("OpenRepository", ".").Return(repo, nil)
+			gitOps := &gitOperations{ctx: ctx, gitUtil: mockGitUtil}
+
+			// Act
+			branch, err := gitOps.getDefaultBranchFromRemoteHead(".", "origin")
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.ErrorContains(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expected, branch)
+		})
+	}
+}
@@ -17,8 +17,9 @@ import (
 )
 
 type gitCommitPush struct {
-	gitPatToken string
-	gitOps      gitops.GitOperations
+	gitPatToken   string
+	offlineStrict bool
+	gitOps        gitops.GitOperations
 }
 
 var (
@@ -97,6 +98,12 @@ func (r *gitCommitPush) Create(ctx context.Context, req resource.CreateRequest,
 	}
 	commitMessage := data.CommitMessage.ValueString()
 
+	if r.offlineStrict && data.EnablePush.ValueBool() {
+		tflog.Error(ctx, OfflineStrictVcsDisabled)
+		resp.Diagnostics.AddError(OfflineStrictVcsDisabled, OfflineStrictVcsDisabledDetailed)
+		return
+	}
+
 	tflog.Info(ctx, "Executing Git Commit")
 	commitHash, err := r.gitOps.CreateCommit(dirPath, commitMessage)
 	if err != nil {
@@ -152,10 +159,11 @@ func (r *gitCommitPush) Configure(_ context.Context, req resource.ConfigureReque
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected TF_GIT_PAT_TOKEN Found",
-			fmt.Sprintf("providerResourceData from context is %s.", providerResourceData),
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
 		)
 
 		return
 	}
 	r.gitPatToken = providerResourceData.GitPatToken
+	r.offlineStrict = providerResourceData.OfflineStrict
 }
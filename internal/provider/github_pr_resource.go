@@ -11,13 +11,16 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type githubPr struct {
-	gitPatToken string
-	gitOps      gitops.GitOperations
+	gitPatToken   string
+	offlineStrict bool
+	gitOps        gitops.GitOperations
 }
 
 var (
@@ -65,12 +68,19 @@ func (r *githubPr) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Required:            true,
 			},
 			"destin_branch": schema.StringAttribute{
-				MarkdownDescription: "The Base branch into which the PR will be merged into.",
-				Required:            true,
+				MarkdownDescription: "The Base branch into which the PR will be merged into. Defaults to the default branch of the repository.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"pull_request_url": schema.StringAttribute{
 				MarkdownDescription: "The URL of the Pull Request created.",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"summary": schema.StringAttribute{
 				MarkdownDescription: "Summary of the Git Commit Resource.",
@@ -88,6 +98,23 @@ func (r *githubPr) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	if r.offlineStrict {
+		tflog.Error(ctx, OfflineStrictVcsDisabled)
+		resp.Diagnostics.AddError(OfflineStrictVcsDisabled, OfflineStrictVcsDisabledDetailed)
+		return
+	}
+
+	if data.DestinBranch.IsUnknown() || data.DestinBranch.ValueString() == "" {
+		defaultBranch, err := r.gitOps.GetDefaultBranch(data.RepoIdentifier.ValueString())
+		if err != nil {
+			tflog.Error(ctx, "Error getting default branch: "+err.Error())
+			resp.Diagnostics.AddError("Error getting default branch: ", err.Error())
+			return
+		}
+		tflog.Info(ctx, "Using default branch of the repository as base branch", map[string]any{"branch": defaultBranch})
+		data.DestinBranch = types.StringValue(defaultBranch)
+	}
+
 	createPrParams := gitUtil.PullRequestParams{
 		RepoIdentifier: data.RepoIdentifier.ValueString(),
 		BaseBranch:     data.DestinBranch.ValueString(),
@@ -144,4 +171,5 @@ func (r *githubPr) Configure(_ context.Context, req resource.ConfigureRequest, r
 	}
 
 	r.gitPatToken = providerResourceData.GitPatToken
+	r.offlineStrict = providerResourceData.OfflineStrict
 }
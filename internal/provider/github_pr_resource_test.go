@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubPrUpdateWithOmittedDestinBranch(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	ctx := context.Background()
+	prResource := &githubPr{}
+	schemaResp := &resource.SchemaResponse{}
+	prResource.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	prValues := func(destinBranch any, prUrl any, prBody string) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"repo_identifier":  tftypes.NewValue(tftypes.String, "example-org/example-repo"),
+			"pr_title":         tftypes.NewValue(tftypes.String, "Migrate to HCP Terraform"),
+			"pr_body":          tftypes.NewValue(tftypes.String, prBody),
+			"source_branch":    tftypes.NewValue(tftypes.String, "tfmigrate"),
+			"destin_branch":    tftypes.NewValue(tftypes.String, destinBranch),
+			"summary":          tftypes.NewValue(tftypes.String, "Pull request created."),
+			"pull_request_url": tftypes.NewValue(tftypes.String, prUrl),
+		})
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: prValues("main", "https://github.com/example-org/example-repo/pull/1", "Initial body")}
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: prValues(nil, nil, "Updated body")}
+	// Terraform plans omitted computed attributes as unknown, before the plan modifiers run.
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: prValues(tftypes.UnknownValue, tftypes.UnknownValue, "Updated body")}
+	for _, name := range []string{"destin_branch", "pull_request_url"} {
+		attribute, ok := schemaResp.Schema.Attributes[name].(schema.StringAttribute)
+		r.True(ok)
+		var stateValue, configValue, planValue types.String
+		r.False(state.GetAttribute(ctx, path.Root(name), &stateValue).HasError())
+		r.False(config.GetAttribute(ctx, path.Root(name), &configValue).HasError())
+		r.False(plan.GetAttribute(ctx, path.Root(name), &planValue).HasError())
+		modifierResp := &planmodifier.StringResponse{PlanValue: planValue}
+		for _, modifier := range attribute.PlanModifiers {
+			modifier.PlanModifyString(ctx, planmodifier.StringRequest{
+				Path:        path.Root(name),
+				StateValue:  stateValue,
+				ConfigValue: configValue,
+				PlanValue:   modifierResp.PlanValue,
+				State:       state,
+				Config:      config,
+				Plan:        plan,
+			}, modifierResp)
+		}
+		r.False(plan.SetAttribute(ctx, path.Root(name), modifierResp.PlanValue).HasError())
+	}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: state.Raw.Copy()}}
+
+	// Act
+	prResource.Update(ctx, resource.UpdateRequest{Plan: plan, State: state, Config: config}, resp)
+
+	// Assert
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	var data GithubPrModel
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.Equal(types.StringValue("main"), data.DestinBranch)
+	r.Equal(types.StringValue("https://github.com/example-org/example-repo/pull/1"), data.PrUrl)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &migrationCandidatesDataSource{}
+	_ datasource.DataSourceWithConfigure = &migrationCandidatesDataSource{}
+)
+
+// NewMigrationCandidatesDataSource is a helper function to simplify the provider implementation.
+func NewMigrationCandidatesDataSource() datasource.DataSource {
+	return &migrationCandidatesDataSource{}
+}
+
+// migrationCandidatesDataSource is the data source implementation.
+type migrationCandidatesDataSource struct {
+	providerResourceData ProviderResourceData
+}
+
+// migrationCandidatesDataSourceModel describes the data source data model.
+type migrationCandidatesDataSourceModel struct {
+	Org        types.String              `tfsdk:"org"`
+	Filters    []explorerFilterModel     `tfsdk:"filters"`
+	Candidates []migrationCandidateModel `tfsdk:"candidates"`
+}
+
+// explorerFilterModel describes a single filter of the Explorer API.
+type explorerFilterModel struct {
+	Field    types.String `tfsdk:"field"`
+	Operator types.String `tfsdk:"operator"`
+	Value    types.String `tfsdk:"value"`
+}
+
+// migrationCandidateModel describes a workspace returned by the Explorer API.
+type migrationCandidateModel struct {
+	WorkspaceName     types.String `tfsdk:"workspace_name"`
+	ProjectName       types.String `tfsdk:"project_name"`
+	TerraformVersion  types.String `tfsdk:"terraform_version"`
+	CurrentRunStatus  types.String `tfsdk:"current_run_status"`
+	Drifted           types.Bool   `tfsdk:"drifted"`
+	Providers         types.String `tfsdk:"providers"`
+	Modules           types.String `tfsdk:"modules"`
+	VcsRepoIdentifier types.String `tfsdk:"vcs_repo_identifier"`
+}
+
+// explorerWorkspace is a workspace row of the Explorer API response.
+type explorerWorkspace struct {
+	WorkspaceName     string `json:"workspace-name"`
+	ProjectName       string `json:"project-name"`
+	TerraformVersion  string `json:"workspace-terraform-version"`
+	CurrentRunStatus  string `json:"current-run-status"`
+	Drifted           bool   `json:"drifted"`
+	Providers         string `json:"providers"`
+	Modules           string `json:"modules"`
+	VcsRepoIdentifier string `json:"vcs-repo-identifier"`
+}
+
+// explorerResponse is a page of the Explorer API response.
+type explorerResponse struct {
+	Data []struct {
+		Attributes explorerWorkspace `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		Pagination struct {
+			NextPage int `json:"next-page"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// Metadata returns the data source type name.
+func (d *migrationCandidatesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_migration_candidates"
+}
+
+// Schema defines the schema for the data source.
+func (d *migrationCandidatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`tfmigrate_migration_candidates` queries the HCP Terraform Explorer API for the workspaces of an organization and lists them as migration candidates. Workspaces without drift whose current run was applied are listed first, as they are the safest to migrate.",
+		Attributes: map[string]schema.Attribute{
+			"org": schema.StringAttribute{
+				MarkdownDescription: "Organization name",
+				Required:            true,
+			},
+			"filters": schema.ListNestedAttribute{
+				MarkdownDescription: "Explorer API filters a workspace must match, e.g. `{ field = \"providers\", operator = \"contains\", value = \"aws\" }`.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"field": schema.StringAttribute{
+							MarkdownDescription: "The Explorer API field to filter on, e.g. `providers`, `modules`, `drifted` or `workspace-terraform-version`.",
+							Required:            true,
+						},
+						"operator": schema.StringAttribute{
+							MarkdownDescription: "The Explorer API filter operator, e.g. `is`, `contains` or `gteq`.",
+							Required:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value to compare the field with.",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"candidates": schema.ListNestedAttribute{
+				MarkdownDescription: "The workspaces matching the filters, safest to migrate first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"workspace_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the workspace.",
+							Computed:            true,
+						},
+						"project_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the project the workspace belongs to.",
+							Computed:            true,
+						},
+						"terraform_version": schema.StringAttribute{
+							MarkdownDescription: "The Terraform version configured on the workspace.",
+							Computed:            true,
+						},
+						"current_run_status": schema.StringAttribute{
+							MarkdownDescription: "The status of the current run of the workspace.",
+							Computed:            true,
+						},
+						"drifted": schema.BoolAttribute{
+							MarkdownDescription: "Whether drift was detected on the workspace.",
+							Computed:            true,
+						},
+						"providers": schema.StringAttribute{
+							MarkdownDescription: "Comma separated list of the providers used by the workspace.",
+							Computed:            true,
+						},
+						"modules": schema.StringAttribute{
+							MarkdownDescription: "Comma separated list of the modules used by the workspace.",
+							Computed:            true,
+						},
+						"vcs_repo_identifier": schema.StringAttribute{
+							MarkdownDescription: "The VCS repository connected to the workspace, if any.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *migrationCandidatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data migrationCandidatesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, tfeOp := newTfeOperation(ctx, d.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	tfeClient, err := newTfeClient(ctx, d.providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing client ", tfeOp.errorDetail(err))
+		return
+	}
+
+	org := data.Org.ValueString()
+	workspaces, err := listExplorerWorkspaces(ctx, tfeClient, org, data.Filters)
+	if err != nil {
+		tflog.Error(ctx, "Error querying the explorer API of organization "+org, map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error querying the explorer API of organization "+org, tfeOp.errorDetail(err))
+		return
+	}
+	prioritizeMigrationCandidates(workspaces)
+	tflog.Info(ctx, "Fetched migration candidates", map[string]any{"org": org, "count": len(workspaces)})
+
+	data.Candidates = make([]migrationCandidateModel, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		data.Candidates = append(data.Candidates, migrationCandidateModel{
+			WorkspaceName:     types.StringValue(workspace.WorkspaceName),
+			ProjectName:       types.StringValue(workspace.ProjectName),
+			TerraformVersion:  types.StringValue(workspace.TerraformVersion),
+			CurrentRunStatus:  types.StringValue(workspace.CurrentRunStatus),
+			Drifted:           types.BoolValue(workspace.Drifted),
+			Providers:         types.StringValue(workspace.Providers),
+			Modules:           types.StringValue(workspace.Modules),
+			VcsRepoIdentifier: types.StringValue(workspace.VcsRepoIdentifier),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listExplorerWorkspaces returns all workspaces of the organization matching the filters, following pagination.
+func listExplorerWorkspaces(ctx context.Context, client *tfe.Client, org string, filters []explorerFilterModel) ([]explorerWorkspace, error) {
+	queryParams := url.Values{"type": []string{"workspaces"}}
+	for i, filter := range filters {
+		key := fmt.Sprintf("filter[%d][%s][%s][0]", i, filter.Field.ValueString(), filter.Operator.ValueString())
+		queryParams.Set(key, filter.Value.ValueString())
+	}
+
+	var workspaces []explorerWorkspace
+	for page := 1; page != 0; {
+		queryParams.Set("page[number]", strconv.Itoa(page))
+		req, err := client.NewRequestWithAdditionalQueryParams("GET", fmt.Sprintf("organizations/%s/explorer", url.PathEscape(org)), nil, queryParams)
+		if err != nil {
+			return nil, err
+		}
+
+		var body bytes.Buffer
+		if err = req.Do(ctx, &body); err != nil {
+			return nil, err
+		}
+		var explorerPage explorerResponse
+		if err = json.Unmarshal(body.Bytes(), &explorerPage); err != nil {
+			return nil, fmt.Errorf("failed to parse explorer API response: %w", err)
+		}
+		for _, row := range explorerPage.Data {
+			workspaces = append(workspaces, row.Attributes)
+		}
+		page = explorerPage.Meta.Pagination.NextPage
+	}
+	return workspaces, nil
+}
+
+// prioritizeMigrationCandidates sorts workspaces without drift whose current run was applied first, then by name.
+func prioritizeMigrationCandidates(workspaces []explorerWorkspace) {
+	rank := func(workspace explorerWorkspace) int {
+		r := 0
+		if workspace.Drifted {
+			r += 2
+		}
+		if workspace.CurrentRunStatus != string(tfe.RunApplied) {
+			r++
+		}
+		return r
+	}
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		if rank(workspaces[i]) != rank(workspaces[j]) {
+			return rank(workspaces[i]) < rank(workspaces[j])
+		}
+		return workspaces[i].WorkspaceName < workspaces[j].WorkspaceName
+	})
+}
+
+func (d *migrationCandidatesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerResourceData, ok := req.ProviderData.(ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Found",
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
+		)
+		return
+	}
+	d.providerResourceData = providerResourceData
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListExplorerWorkspaces(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	pages := map[string]string{
+		"1": `{"data": [{"attributes": {"workspace-name": "drifted", "drifted": true, "current-run-status": "applied"}},
+		                {"attributes": {"workspace-name": "b-ready", "current-run-status": "applied", "providers": "aws"}}],
+		       "meta": {"pagination": {"next-page": 2}}}`,
+		"2": `{"data": [{"attributes": {"workspace-name": "errored", "current-run-status": "errored"}},
+		                {"attributes": {"workspace-name": "a-ready", "current-run-status": "applied"}}],
+		       "meta": {"pagination": {"next-page": null}}}`,
+	}
+	var queries []url.Values
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("TFP-API-Version", "2.6")
+		if !strings.HasSuffix(req.URL.Path, "/organizations/example-org/explorer") {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		queries = append(queries, req.URL.Query())
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = fmt.Fprint(w, pages[req.URL.Query().Get("page[number]")])
+	}))
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	r.NoError(err)
+	t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+	client, err := NewTfeClientFactory(server.Client()).NewTfeClient(context.Background(), ProviderResourceData{Hostname: serverUrl.Host})
+	r.NoError(err)
+
+	filters := []explorerFilterModel{{
+		Field:    types.StringValue("providers"),
+		Operator: types.StringValue("contains"),
+		Value:    types.StringValue("aws"),
+	}}
+
+	// Act
+	workspaces, err := listExplorerWorkspaces(context.Background(), client, "example-org", filters)
+	prioritizeMigrationCandidates(workspaces)
+
+	// Assert
+	r.NoError(err)
+	r.Len(queries, 2)
+	r.Equal("workspaces", queries[0].Get("type"))
+	r.Equal("aws", queries[0].Get("filter[0][providers][contains][0]"))
+	names := make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		names = append(names, workspace.WorkspaceName)
+	}
+	r.Equal([]string{"a-ready", "b-ready", "errored", "drifted"}, names)
+}
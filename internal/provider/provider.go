@@ -6,10 +6,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"terraform-provider-tfmigrate/internal/constants"
 	gitops "terraform-provider-tfmigrate/internal/helper"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
 	gitUtil "terraform-provider-tfmigrate/internal/util/vcs/git"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -30,8 +32,9 @@ var (
 )
 
 const (
-	GitTokenEnvName  = "TF_GIT_PAT_TOKEN"
-	HcpTerraformHost = "app.terraform.io"
+	GitTokenEnvName   = "TF_GIT_PAT_TOKEN"
+	TfeProjectEnvName = "TFE_PROJECT"
+	HcpTerraformHost  = "app.terraform.io"
 )
 
 // tfmProvider is the provider implementation.
@@ -39,28 +42,61 @@ type tfmProvider struct {
 	version                     string
 	gitOps                      gitops.GitOperations
 	remoteVcsSvcProviderFactory gitRemoteSvcProvider.RemoteVcsSvcProviderFactory
+	tfeClientFactory            TfeClientFactory
+}
+
+// Option configures optional dependencies of the provider.
+type Option func(*tfmProvider)
+
+// WithTfeClientFactory replaces the factory used to create TFE clients, e.g. with one returning a fake client.
+func WithTfeClientFactory(tfeClientFactory TfeClientFactory) Option {
+	return func(p *tfmProvider) {
+		p.tfeClientFactory = tfeClientFactory
+	}
+}
+
+// WithHttpClient sets the http client used for requests to the TFE API.
+// The extra_headers and debug_http settings are still applied on top of its transport.
+func WithHttpClient(httpClient *http.Client) Option {
+	return WithTfeClientFactory(NewTfeClientFactory(httpClient))
 }
 
 // tfmProviderModel maps provider schema data to a Go type.
 type tfmProviderModel struct {
-	GitPatToken types.String `tfsdk:"git_pat_token"`
-	Hostname    types.String `tfsdk:"hostname"`
+	GitPatToken   types.String `tfsdk:"git_pat_token"`
+	Hostname      types.String `tfsdk:"hostname"`
+	ExtraHeaders  types.Map    `tfsdk:"extra_headers"`
+	DebugHttp     types.Bool   `tfsdk:"debug_http"`
+	OfflineStrict types.Bool   `tfsdk:"offline_strict"`
+	MaxApiCalls   types.Int64  `tfsdk:"max_api_calls"`
+	TlsInsecure   types.Bool   `tfsdk:"tls_insecure_skip_verify"`
 }
 
 // ProviderResourceData holds the provider configuration data.
 type ProviderResourceData struct {
-	GitPatToken string
-	Hostname    string
+	GitPatToken      string
+	Hostname         string
+	ExtraHeaders     map[string]string
+	DebugHttp        bool
+	OfflineStrict    bool
+	MaxApiCalls      int
+	TlsInsecure      bool
+	TfeClientFactory TfeClientFactory
 }
 
 // New is a helper function to simplify provider server and testing implementation.
-func New(version string) func() provider.Provider {
+func New(version string, opts ...Option) func() provider.Provider {
 	return func() provider.Provider {
-		return &tfmProvider{
+		p := &tfmProvider{
 			version:                     version,
 			gitOps:                      gitops.NewGitOperations(context.Background(), gitUtil.NewGitUtil(context.Background())),
 			remoteVcsSvcProviderFactory: gitRemoteSvcProvider.NewRemoteSvcProviderFactory(context.Background()),
+			tfeClientFactory:            NewTfeClientFactory(nil),
 		}
+		for _, opt := range opts {
+			opt(p)
+		}
+		return p
 	}
 }
 
@@ -82,7 +118,29 @@ func (p *tfmProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 			"hostname": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   false,
-				Description: "The hostname of the TFE instance to connect to. Defaults to HCP Terraform at app.terraform.io.",
+				Description: "The hostname of the TFE instance to connect to. Defaults to HCP Terraform at app.terraform.io. If a TFE token is available for the hostname, connectivity to its API is checked when the provider is configured.",
+			},
+			"extra_headers": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Additional HTTP headers to send with every request to the TFE API, e.g. tenant IDs or tokens required by a corporate proxy.",
+			},
+			"debug_http": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Log the method, URL, status and request ID of every TFE API request. Tokens, headers and bodies are never logged.",
+			},
+			"offline_strict": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Disable every outbound call other than to the TFE API. The Git PAT token is not validated against the GitHub or GitLab API, and pushing commits or creating pull requests fails. Cannot be set together with git_pat_token.",
+			},
+			"max_api_calls": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of TFE API calls a single resource or data source operation may make, including retries. The operation fails once the limit is exceeded, which stops runaway loops. Defaults to no limit.",
+			},
+			"tls_insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Skip the verification of the TLS certificate of the TFE instance, e.g. for a TFE instance with a self-signed certificate. The TFE token is then sent over an unverified connection. Defaults to false.",
 			},
 		},
 	}
@@ -115,6 +173,13 @@ func (p *tfmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			"The provider cannot initialize the TFE API client as the hostname is unknown. Set it in configuration.",
 		)
 	}
+	if config.ExtraHeaders.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("extra_headers"),
+			"Unknown Extra Headers",
+			"The provider cannot initialize the TFE API client as the extra headers are unknown. Set them in configuration.",
+		)
+	}
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -132,6 +197,103 @@ func (p *tfmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		hostname = config.Hostname.ValueString()
 	}
 
+	extraHeaders := make(map[string]string)
+	if !config.ExtraHeaders.IsNull() {
+		resp.Diagnostics.Append(config.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	for name := range extraHeaders {
+		if http.CanonicalHeaderKey(name) == "Authorization" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("extra_headers"),
+				"Invalid Extra Header",
+				"The Authorization header is managed by the provider and cannot be set in extra_headers.",
+			)
+			return
+		}
+	}
+
+	maxApiCalls := config.MaxApiCalls.ValueInt64()
+	if maxApiCalls < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_api_calls"),
+			"Invalid Max API Calls",
+			"max_api_calls must not be negative.",
+		)
+		return
+	}
+
+	offlineStrict := config.OfflineStrict.ValueBool()
+	if offlineStrict && !config.GitPatToken.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("git_pat_token"),
+			OfflineStrictVcsDisabled,
+			"git_pat_token cannot be set together with offline_strict, as the token is only used to call the GitHub or GitLab API.",
+		)
+		return
+	}
+
+	if offlineStrict {
+		tflog.Info(ctx, "offline_strict is enabled, skipping Git PAT token validation against the VCS API")
+		gitPatToken = ""
+	} else {
+		p.validateGitPatToken(gitPatToken, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Set the provider resource data
+	providerResourceData := ProviderResourceData{
+		GitPatToken:      gitPatToken,
+		Hostname:         hostname,
+		ExtraHeaders:     extraHeaders,
+		DebugHttp:        config.DebugHttp.ValueBool(),
+		OfflineStrict:    offlineStrict,
+		MaxApiCalls:      int(maxApiCalls),
+		TlsInsecure:      config.TlsInsecure.ValueBool(),
+		TfeClientFactory: p.tfeClientFactory,
+	}
+
+	p.validateTfeConnectivity(ctx, providerResourceData, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.ResourceData = providerResourceData
+	resp.DataSourceData = providerResourceData
+}
+
+// validateTfeConnectivity checks that the TFE API of the configured hostname is reachable, so a wrong hostname or a
+// broken proxy is reported when the provider is configured instead of on the first resource call.
+// The check is skipped when no TFE token is available for the hostname, as the git resources do not need the TFE API.
+func (p *tfmProvider) validateTfeConnectivity(ctx context.Context, providerResourceData ProviderResourceData, resp *provider.ConfigureResponse) {
+	hostname := providerResourceData.Hostname
+	if _, err := tfeUtil.ReadTfeToken(ctx, hostname); err != nil {
+		tflog.Info(ctx, "No TFE token found, skipping the TFE connectivity check", map[string]any{"hostname": hostname, "error": err})
+		return
+	}
+
+	ctx, tfeOp := newTfeOperation(ctx, providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	tfeClient, err := newTfeClient(ctx, providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, TfeConnectivityFailed, map[string]any{"hostname": hostname, "error": err})
+		resp.Diagnostics.AddError(TfeConnectivityFailed, fmt.Sprintf(TfeConnectivityFailedDetailed, hostname, tfeOp.errorDetail(err)))
+		return
+	}
+	if tfeClient.RemoteAPIVersion() == "" {
+		tflog.Error(ctx, TfeConnectivityFailed, map[string]any{"hostname": hostname})
+		resp.Diagnostics.AddError(TfeConnectivityFailed, fmt.Sprintf(TfeConnectivityFailedDetailed, hostname, "the server did not report a TFE API version"))
+		return
+	}
+	tflog.Info(ctx, "Connected to the TFE API", map[string]any{"hostname": hostname, "apiVersion": tfeClient.RemoteAPIVersion()})
+}
+
+// validateGitPatToken validates the Git PAT token against the remote service provider of the current repository.
+func (p *tfmProvider) validateGitPatToken(gitPatToken string, resp *provider.ConfigureResponse) {
 	// Validate configurations
 	if gitPatToken == "" {
 		resp.Diagnostics.AddError(
@@ -170,17 +332,15 @@ func (p *tfmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		resp.Diagnostics.AddWarning("", suggestion)
 		return
 	}
-
-	// Set the provider resource data
-	resp.ResourceData = ProviderResourceData{
-		GitPatToken: gitPatToken,
-		Hostname:    hostname,
-	}
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *tfmProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewTfeApiDataSource,
+		NewMigrationCandidatesDataSource,
+		NewStateParityDataSource,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
@@ -193,5 +353,7 @@ func (p *tfmProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewGithubPrResource,
 		NewDirectoryActionResource,
 		NewStateMigrationResource,
+		NewStateRewriteResource,
+		NewWorkspaceUnlockResource,
 	}
 }
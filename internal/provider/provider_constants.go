@@ -17,4 +17,23 @@ const (
 	TerraformInitFailed  = "Terraform Init Failed."
 	TerraformPlanSuccess = "Add %d, Change %d, Remove %d"
 	TerraformPlanFailed  = "Terrform Plan Failed."
+
+	TerraformValidateFailed = "Terraform Validate Failed:"
+
+	TfeConnectivityFailed         = "Unable to connect to the TFE API."
+	TfeConnectivityFailedDetailed = "The provider could not reach the TFE API of %s: %s. Check the hostname, extra_headers and any proxy between the provider and the TFE instance."
+
+	OfflineStrictVcsDisabled         = "VCS access is disabled by offline_strict."
+	OfflineStrictVcsDisabledDetailed = "The provider is configured with offline_strict, which only allows calls to the TFE API. Disable offline_strict to push commits or create pull requests."
+
+	MaxApiCallsExceededDetailed = "The operation made more than %d TFE API calls, the limit set by max_api_calls."
+
+	StateRewriteSuccess           = "Moved %d address(es)."
+	StateRewriteInvalidAddressMap = "Invalid address_map."
+
+	StateFormatInvalid         = "State is not plain JSON."
+	StateFormatInvalidDetailed = "The state of workspace %s is not a plain JSON Terraform state (%s). It may be encrypted or wrapped by external tooling; set state_transform_command to a command that writes the plain state to stdout."
+
+	WorkspaceUnlockSuccess = "Unlocked %d of %d workspace(s) locked by the current user."
+	WorkspaceLockReason    = "Locked by tfmigrate while migrating state."
 )
@@ -4,8 +4,19 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-tfmigrate/_mocks/helper_mocks/gitops_mocks"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -27,3 +38,127 @@ provider "tfmigrate" {
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"tfmigrate": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+func TestConfigureOfflineStrict(t *testing.T) {
+	for name, tc := range map[string]struct {
+		gitPatToken any
+		expectedErr string
+	}{
+		"offlineStrictSkipsVcsValidation": {},
+		"offlineStrictWithGitPatToken": {
+			gitPatToken: "ghp_token",
+			expectedErr: OfflineStrictVcsDisabled,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			t.Setenv(GitTokenEnvName, "ghp_env_token")
+			// No TFE credentials are available, so the TFE connectivity check is skipped.
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv(tfeUtil.CliConfigFileEnvName, "")
+			p, ok := New("test")().(*tfmProvider)
+			r.True(ok)
+			// Any call to the VCS helpers fails the test, as none is expected in offline mode.
+			p.gitOps = gitops_mocks.NewMockGitOperations(t)
+
+			schemaResp := &provider.SchemaResponse{}
+			p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+			config := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+				"git_pat_token":            tftypes.NewValue(tftypes.String, tc.gitPatToken),
+				"hostname":                 tftypes.NewValue(tftypes.String, nil),
+				"extra_headers":            tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+				"debug_http":               tftypes.NewValue(tftypes.Bool, nil),
+				"offline_strict":           tftypes.NewValue(tftypes.Bool, true),
+				"max_api_calls":            tftypes.NewValue(tftypes.Number, nil),
+				"tls_insecure_skip_verify": tftypes.NewValue(tftypes.Bool, nil),
+			})
+			resp := &provider.ConfigureResponse{}
+
+			// Act
+			p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, resp)
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.True(resp.Diagnostics.HasError())
+				r.Equal(tc.expectedErr, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			providerResourceData, ok := resp.ResourceData.(ProviderResourceData)
+			r.True(ok)
+			r.True(providerResourceData.OfflineStrict)
+			r.Empty(providerResourceData.GitPatToken)
+		})
+	}
+}
+
+func TestConfigureTfeConnectivity(t *testing.T) {
+	for name, tc := range map[string]struct {
+		token         string
+		apiVersion    string
+		factoryErr    error
+		expectedCalls int
+		expectedErr   string
+	}{
+		"skippedWithoutToken": {},
+		"connected": {
+			token:         "test-token",
+			apiVersion:    "2.6",
+			expectedCalls: 1,
+		},
+		"clientCreationFailed": {
+			token:         "test-token",
+			factoryErr:    errors.New("proxyconnect tcp: connection refused"),
+			expectedCalls: 1,
+			expectedErr:   TfeConnectivityFailed,
+		},
+		"notTfeApi": {
+			token:         "test-token",
+			expectedCalls: 1,
+			expectedErr:   TfeConnectivityFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv(tfeUtil.CliConfigFileEnvName, "")
+			t.Setenv(tfeUtil.TokenEnvNamePrefix+"tfe_example_com", tc.token)
+			factory := &fakeTfeClientFactory{err: tc.factoryErr}
+			if tc.factoryErr == nil && tc.token != "" {
+				factory.client = newTestTfeClient(t, tc.apiVersion)
+			}
+			p, ok := New("test", WithTfeClientFactory(factory))().(*tfmProvider)
+			r.True(ok)
+			p.gitOps = gitops_mocks.NewMockGitOperations(t)
+
+			schemaResp := &provider.SchemaResponse{}
+			p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+			config := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+				"git_pat_token":            tftypes.NewValue(tftypes.String, nil),
+				"hostname":                 tftypes.NewValue(tftypes.String, "tfe.example.com"),
+				"extra_headers":            tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+				"debug_http":               tftypes.NewValue(tftypes.Bool, nil),
+				"offline_strict":           tftypes.NewValue(tftypes.Bool, true),
+				"max_api_calls":            tftypes.NewValue(tftypes.Number, nil),
+				"tls_insecure_skip_verify": tftypes.NewValue(tftypes.Bool, nil),
+			})
+			resp := &provider.ConfigureResponse{}
+
+			// Act
+			p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, resp)
+
+			// Assert
+			r.Equal(tc.expectedCalls, factory.calls)
+			if tc.expectedErr != "" {
+				r.True(resp.Diagnostics.HasError())
+				r.Equal(tc.expectedErr, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		})
+	}
+}
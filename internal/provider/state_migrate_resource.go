@@ -4,18 +4,21 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
-	"path/filepath"
+	"os/exec"
+	"strings"
 	"terraform-provider-tfmigrate/internal/terraform"
+	httpUtil "terraform-provider-tfmigrate/internal/util/http"
+	"time"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -23,27 +26,29 @@ import (
 )
 
 type stateMigration struct {
-	Hostname string
+	providerResourceData  ProviderResourceData
+	newTerraformOperation func(dirPath string) terraform.TerraformOperationInterface
 }
 
 var (
 	_ resource.Resource = &stateMigration{}
 )
 
-const TfcTokenPath = ".terraform.d/credentials.tfrc.json"
 const TfcScheme = "https"
 
-var tfeClient *tfe.Client
-
 func NewStateMigrationResource() resource.Resource {
-	return &stateMigration{}
+	return &stateMigration{
+		newTerraformOperation: newTerraformOperation,
+	}
 }
 
 type stateMigrationModel struct {
-	DirectoryPath  types.String `tfsdk:"directory_path"`
-	Org            types.String `tfsdk:"org"`
-	LocalWorkspace types.String `tfsdk:"local_workspace"`
-	TFCWorkspace   types.String `tfsdk:"tfc_workspace"`
+	DirectoryPath           types.String `tfsdk:"directory_path"`
+	Org                     types.String `tfsdk:"org"`
+	LocalWorkspace          types.String `tfsdk:"local_workspace"`
+	TFCWorkspace            types.String `tfsdk:"tfc_workspace"`
+	StateTransformCommand   types.List   `tfsdk:"state_transform_command"`
+	ValidateTerraformConfig types.Bool   `tfsdk:"validate_terraform_config"`
 }
 
 func (r *stateMigration) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -70,6 +75,15 @@ func (r *stateMigration) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Terraform cloud workspace name",
 				Required:            true,
 			},
+			"state_transform_command": schema.ListAttribute{
+				MarkdownDescription: "Command and arguments run in `directory_path` to unwrap the pulled state before it is uploaded, e.g. to decrypt state encrypted by external tooling. The pulled state is written to the stdin of the command and its stdout is used as the state.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"validate_terraform_config": schema.BoolAttribute{
+				MarkdownDescription: "Run `terraform validate` in `directory_path` before the state is migrated, to make sure the configuration matching the state is valid. Validation warnings are reported as warnings, and validation errors stop the migration.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -82,9 +96,7 @@ func (r *stateMigration) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	dirPath := data.DirectoryPath.ValueString()
-	tfOps := &terraform.TerraformOperation{
-		DirectoryPath: dirPath,
-	}
+	tfOps := r.newTerraformOperation(dirPath)
 	_, err := os.Stat(dirPath)
 	if err != nil {
 		tflog.Error(ctx, DirPathDoesNotExist)
@@ -99,6 +111,13 @@ func (r *stateMigration) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	if data.ValidateTerraformConfig.ValueBool() {
+		resp.Diagnostics.Append(validateTerraformConfig(ctx, tfOps, dirPath)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	err = tfOps.SelectWorkspace(ctx, data.LocalWorkspace.ValueString())
 	if err != nil {
 		tflog.Error(ctx, "Error selecting workspace ", map[string]any{"error": err})
@@ -112,21 +131,30 @@ func (r *stateMigration) Create(ctx context.Context, req resource.CreateRequest,
 		resp.Diagnostics.AddError("Error downloading state "+data.LocalWorkspace.ValueString(), err.Error())
 		return
 	}
+
+	state, diags := prepareLocalState(ctx, dirPath, data.StateTransformCommand, state, data.LocalWorkspace.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	tflog.Info(ctx, "Migrating state from local ws : "+data.LocalWorkspace.ValueString()+" to tfc : "+data.TFCWorkspace.ValueString(),
-		map[string]interface{}{"state": string(state[:])})
-	if tfeClient == nil {
-		tfeClient, err = newTfeClient(r.Hostname)
-		if err != nil {
-			tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
-			resp.Diagnostics.AddError("Error initializing client ", err.Error())
-			return
-		}
+		map[string]interface{}{"sizeBytes": len(state)})
+	// The client is created per operation as Terraform may run multiple
+	// instances of this resource concurrently.
+	ctx, tfeOp := newTfeOperation(ctx, r.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	tfeClient, err := newTfeClient(ctx, r.providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing client ", tfeOp.errorDetail(err))
+		return
 	}
 	workspace := data.TFCWorkspace.ValueString()
 	workspaceDetails, err := tfeClient.Workspaces.Read(ctx, data.Org.ValueString(), workspace)
 	if err != nil {
 		tflog.Error(ctx, "Error fetching workspace data "+workspace, map[string]any{"error": err})
-		resp.Diagnostics.AddError("Error fetching workspace data "+workspace, err.Error())
+		resp.Diagnostics.AddError("Error fetching workspace data "+workspace, tfeOp.errorDetail(err))
 		return
 	}
 	workspaceId := workspaceDetails.ID
@@ -134,7 +162,7 @@ func (r *stateMigration) Create(ctx context.Context, req resource.CreateRequest,
 	err = uploadState(ctx, state, workspaceId, workspace, tfeClient)
 	if err != nil {
 		tflog.Error(ctx, "Failed to  upload state", map[string]any{"error": err})
-		resp.Diagnostics.AddError("Failed to  upload state ", err.Error())
+		resp.Diagnostics.AddError("Failed to  upload state ", tfeOp.errorDetail(err))
 		return
 	}
 
@@ -167,13 +195,13 @@ func uploadState(ctx context.Context, state []byte, workspaceId string, workspac
 	}
 
 	// Lock the workspace
-	if _, err := client.Workspaces.Lock(ctx, workspaceId, tfe.WorkspaceLockOptions{}); err != nil {
+	if _, err := client.Workspaces.Lock(ctx, workspaceId, tfe.WorkspaceLockOptions{Reason: tfe.String(WorkspaceLockReason)}); err != nil {
 		tflog.Error(ctx, "Failed to lock workspace")
 		return err
 	}
 	defer func() {
-		// Unlock the workspace
-		if _, err := client.Workspaces.Unlock(ctx, workspaceId); err != nil {
+		// Unlock the workspace, even when the context was canceled because the API call budget was exceeded.
+		if _, err := client.Workspaces.Unlock(httpUtil.WithoutApiCallBudget(ctx), workspaceId); err != nil {
 			tflog.Error(ctx, "Failed to unlock workspace")
 		}
 	}()
@@ -188,56 +216,112 @@ func uploadState(ctx context.Context, state []byte, workspaceId string, workspac
 		RawState: state,
 	}
 
+	tflog.Info(ctx, "Uploading state", map[string]any{"workspace": workspace, "sizeBytes": len(state), "serial": meta.Serial})
+	uploadStart := time.Now()
 	stateVersion, err := client.StateVersions.Upload(ctx, workspaceId, options)
 	if err != nil {
-		tflog.Error(ctx, "Failed to upload state")
+		tflog.Error(ctx, "Failed to upload state", map[string]any{"workspace": workspace, "duration": time.Since(uploadStart).String()})
 		return err
 	}
-	tflog.Info(ctx, "State migrated successfully", map[string]any{"workspace": workspace, "id": stateVersion.ID})
+	tflog.Info(ctx, "State migrated successfully", map[string]any{"workspace": workspace, "id": stateVersion.ID, "duration": time.Since(uploadStart).String()})
 	return nil
 }
 
-func newTfeClient(hostname string) (*tfe.Client, error) {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
+// maxTransformStderrLength is the number of bytes of the stderr of state_transform_command included in errors, as
+// the command may print large or sensitive output when it fails.
+const maxTransformStderrLength = 512
 
-	homeDir, err := os.UserHomeDir()
+type stateMeta struct {
+	Version *int
+	Serial  int64
+	Lineage string
+}
+
+// validateTerraformConfig runs terraform validate and reports validation errors as errors and any other
+// validation diagnostics as warnings.
+func validateTerraformConfig(ctx context.Context, tfOps terraform.TerraformOperationInterface, dirPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	diagnostics, err := tfOps.Validate(ctx)
 	if err != nil {
-		return nil, err
+		tflog.Error(ctx, "Error validating terraform configuration ", map[string]any{"error": err})
+		diags.AddError("Error validating terraform configuration "+dirPath, err.Error())
+		return diags
 	}
-	credsFilePath := filepath.Join(homeDir, TfcTokenPath)
-	credsJson, err := os.ReadFile(credsFilePath)
-	if err != nil {
-		return nil, err
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == terraform.TERRAFORM_ERROR_TYPE {
+			diags.AddError(TerraformValidateFailed+" "+diagnostic.Summary, diagnostic.Detail)
+			continue
+		}
+		diags.AddWarning(diagnostic.Summary, diagnostic.Detail)
 	}
+	return diags
+}
 
-	var tfCredentials TfCredentials
-	if err := json.Unmarshal(credsJson, &tfCredentials); err != nil {
-		return nil, errors.New("failed to parse credential file" + err.Error())
+// prepareLocalState runs the state_transform_command, if any, on the state pulled from the local workspace and
+// checks that the result is a plain Terraform state.
+func prepareLocalState(ctx context.Context, dirPath string, transformCommand types.List, state []byte, workspace string) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !transformCommand.IsNull() {
+		var command []string
+		diags.Append(transformCommand.ElementsAs(ctx, &command, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		var err error
+		state, err = transformState(ctx, dirPath, command, state)
+		if err != nil {
+			tflog.Error(ctx, "Error transforming state ", map[string]any{"error": err})
+			diags.AddError("Error transforming state "+workspace, err.Error())
+			return nil, diags
+		}
 	}
-
-	tfcConfig := &tfe.Config{
-		Address:           TfcScheme + "://" + hostname + "/",
-		Token:             tfCredentials.Creds[hostname].Token,
-		RetryServerErrors: true,
-		HTTPClient:        client,
+	if err := validateStateFormat(state); err != nil {
+		tflog.Error(ctx, StateFormatInvalid, map[string]any{"error": err})
+		diags.AddError(StateFormatInvalid, fmt.Sprintf(StateFormatInvalidDetailed, workspace, err.Error()))
+		return nil, diags
 	}
-	return tfe.NewClient(tfcConfig)
+	return state, diags
 }
 
-type TfRemote struct {
-	Token string `json:"token"`
-}
-
-type TfCredentials struct {
-	Creds map[string]TfRemote `json:"credentials"`
+// transformState runs command in dir with the state on its stdin and returns its stdout as the new state.
+func transformState(ctx context.Context, dir string, command []string, state []byte) ([]byte, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, errors.New("state_transform_command must not be empty")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(state)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stderr.String())
+		if len(output) > maxTransformStderrLength {
+			output = output[:maxTransformStderrLength] + "... (truncated)"
+		}
+		return nil, fmt.Errorf("%s failed: %w: %s", command[0], err, output)
+	}
+	return stdout.Bytes(), nil
 }
 
-type stateMeta struct {
-	Serial  int64
-	Lineage string
+// validateStateFormat checks that state is a plain JSON Terraform state, so states encrypted or wrapped by
+// external tooling fail with a clear error instead of a cryptic one during the upload.
+func validateStateFormat(state []byte) error {
+	trimmed := bytes.TrimSpace(state)
+	if len(trimmed) == 0 {
+		return errors.New("the state is empty")
+	}
+	if trimmed[0] != '{' || !json.Valid(trimmed) {
+		return errors.New("the state is not a JSON object")
+	}
+	var meta stateMeta
+	if err := json.Unmarshal(trimmed, &meta); err != nil {
+		return fmt.Errorf("the state is not a Terraform state: %w", err)
+	}
+	if meta.Version == nil || meta.Lineage == "" {
+		return errors.New("the state has no version or lineage")
+	}
+	return nil
 }
 
 func (r *stateMigration) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -250,10 +334,10 @@ func (r *stateMigration) Configure(_ context.Context, req resource.ConfigureRequ
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Github Token Found",
-			fmt.Sprintf("providerResourceData from context is %s.", providerResourceData),
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
 		)
 
 		return
 	}
-	r.Hostname = providerResourceData.Hostname
+	r.providerResourceData = providerResourceData
 }
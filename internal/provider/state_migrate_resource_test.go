@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"terraform-provider-tfmigrate/_mocks/terraform_mocks"
+	"terraform-provider-tfmigrate/internal/terraform"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStateFormat(t *testing.T) {
+	for name, tc := range map[string]struct {
+		state       string
+		expectedErr string
+	}{
+		"plainState": {
+			state: `{"version": 4, "serial": 3, "lineage": "8a1f7e6c-7c5a-4d3b-9e2f-0d6b1c2a3e4f", "resources": []}`,
+		},
+		"emptyState": {
+			state:       " \n",
+			expectedErr: "the state is empty",
+		},
+		"encryptedState": {
+			state:       "vault:v1:8SDd3WHDOjf7mq69CyCqYjBXAiQQAVZRkFM13ok481zoCmHnSeDX9vyf7w==",
+			expectedErr: "the state is not a JSON object",
+		},
+		"wrappedState": {
+			state:       `{"encrypted_data": "c2VjcmV0", "encryption_version": "v0"}`,
+			expectedErr: "the state has no version or lineage",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			err := validateStateFormat([]byte(tc.state))
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+		})
+	}
+}
+
+func TestTransformState(t *testing.T) {
+	for name, tc := range map[string]struct {
+		command       []string
+		expectedState string
+		expectedErr   string
+	}{
+		"stateWrittenToStdinAndReadFromStdout": {
+			command:       []string{"cat"},
+			expectedState: "state",
+		},
+		"emptyCommand": {
+			expectedErr: "state_transform_command must not be empty",
+		},
+		"commandFailed": {
+			command:     []string{"sh", "-c", "echo decryption key not found >&2; exit 1"},
+			expectedErr: "sh failed: exit status 1: decryption key not found",
+		},
+		"commandFailedWithLongStderr": {
+			command:     []string{"sh", "-c", "printf '%0600d' 0 >&2; exit 1"},
+			expectedErr: "sh failed: exit status 1: " + strings.Repeat("0", maxTransformStderrLength) + "... (truncated)",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+
+			// Act
+			state, err := transformState(context.Background(), t.TempDir(), tc.command, []byte("state"))
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expectedState, string(state))
+		})
+	}
+}
+
+func TestValidateTerraformConfig(t *testing.T) {
+	for name, tc := range map[string]struct {
+		diagnostics      []terraform.TerraformValidateDiagnostic
+		validateErr      error
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		"valid": {},
+		"errorsAndWarnings": {
+			diagnostics: []terraform.TerraformValidateDiagnostic{
+				{Severity: "error", Summary: "Missing required argument", Detail: "The argument \"ami\" is required."},
+				{Severity: "warning", Summary: "Deprecated attribute", Detail: "The attribute \"name\" is deprecated."},
+			},
+			expectedErrors:   []string{TerraformValidateFailed + " Missing required argument"},
+			expectedWarnings: []string{"Deprecated attribute"},
+		},
+		"validateFailed": {
+			validateErr:    errors.New("exit status 1"),
+			expectedErrors: []string{"Error validating terraform configuration /config"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			tfOps := new(terraform_mocks.MockTerraformOperation)
+			tfOps.On("Validate", ctx).Return(tc.diagnostics, tc.validateErr)
+
+			// Act
+			diags := validateTerraformConfig(ctx, tfOps, "/config")
+
+			// Assert
+			var errorSummaries, warningSummaries []string
+			for _, d := range diags.Errors() {
+				errorSummaries = append(errorSummaries, d.Summary())
+			}
+			for _, d := range diags.Warnings() {
+				warningSummaries = append(warningSummaries, d.Summary())
+			}
+			r.Equal(tc.expectedErrors, errorSummaries)
+			r.Equal(tc.expectedWarnings, warningSummaries)
+		})
+	}
+}
+
+func TestStateMigrationCreateStopsOnValidationErrors(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	ctx := context.Background()
+	dirPath := t.TempDir()
+	tfOps := new(terraform_mocks.MockTerraformOperation)
+	tfOps.On("ExecuteTerraformInit", ctx).Return(nil)
+	tfOps.On("Validate", ctx).Return([]terraform.TerraformValidateDiagnostic{
+		{Severity: "error", Summary: "Unsupported argument", Detail: "An argument named \"foo\" is not expected here."},
+	}, nil)
+	migration := &stateMigration{
+		newTerraformOperation: func(string) terraform.TerraformOperationInterface { return tfOps },
+	}
+	schemaResp := &resource.SchemaResponse{}
+	migration.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"directory_path":            tftypes.NewValue(tftypes.String, dirPath),
+		"org":                       tftypes.NewValue(tftypes.String, "example-org"),
+		"local_workspace":           tftypes.NewValue(tftypes.String, "default"),
+		"tfc_workspace":             tftypes.NewValue(tftypes.String, "example-workspace"),
+		"state_transform_command":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"validate_terraform_config": tftypes.NewValue(tftypes.Bool, true),
+	})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+
+	// Act
+	migration.Create(ctx, resource.CreateRequest{Plan: plan}, resp)
+
+	// Assert
+	r.True(resp.Diagnostics.HasError())
+	r.Equal(TerraformValidateFailed+" Unsupported argument", resp.Diagnostics.Errors()[0].Summary())
+	tfOps.AssertNotCalled(t, "SelectWorkspace", ctx, mock.Anything)
+	tfOps.AssertNotCalled(t, "StatePull", ctx)
+	r.True(resp.State.Raw.IsNull())
+}
+
+func TestUploadStateUnlocksWhenApiCallBudgetExceeded(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	const workspaceId = "ws-CZcmD7eagjhyX0vN"
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		received = append(received, req.Method+" "+req.URL.Path)
+		mu.Unlock()
+		w.Header().Set("TFP-API-Version", "2.6")
+		if req.URL.Path == "/api/v2/ping" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = fmt.Fprintf(w, `{"data":{"id":%q,"type":"workspaces","attributes":{"locked":true}}}`, workspaceId)
+	}))
+	defer server.Close()
+	serverUrl, err := url.Parse(server.URL)
+	r.NoError(err)
+	t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+	providerResourceData := ProviderResourceData{
+		Hostname:         serverUrl.Host,
+		MaxApiCalls:      1,
+		TfeClientFactory: NewTfeClientFactory(server.Client()),
+	}
+	client, err := newTfeClient(context.Background(), providerResourceData)
+	r.NoError(err)
+	ctx, tfeOp := newTfeOperation(context.Background(), providerResourceData)
+	defer tfeOp.close()
+	state := []byte(`{"version": 4, "serial": 3, "lineage": "8a1f7e6c-7c5a-4d3b-9e2f-0d6b1c2a3e4f", "resources": []}`)
+
+	// Act
+	err = uploadState(ctx, state, workspaceId, "example-workspace", client)
+
+	// Assert
+	r.Error(err)
+	r.True(tfeOp.apiCalls.Exceeded())
+	r.Equal([]string{
+		"GET /api/v2/ping",
+		"POST /api/v2/workspaces/" + workspaceId + "/actions/lock",
+		"POST /api/v2/workspaces/" + workspaceId + "/actions/unlock",
+	}, received)
+}
@@ -0,0 +1,281 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"terraform-provider-tfmigrate/internal/terraform"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &stateParityDataSource{}
+	_ datasource.DataSourceWithConfigure = &stateParityDataSource{}
+)
+
+// NewStateParityDataSource is a helper function to simplify the provider implementation.
+func NewStateParityDataSource() datasource.DataSource {
+	return &stateParityDataSource{
+		newTerraformOperation: newTerraformOperation,
+	}
+}
+
+// stateParityDataSource is the data source implementation.
+type stateParityDataSource struct {
+	providerResourceData  ProviderResourceData
+	newTerraformOperation func(dirPath string) terraform.TerraformOperationInterface
+}
+
+// stateParityDataSourceModel describes the data source data model.
+type stateParityDataSourceModel struct {
+	DirectoryPath         types.String `tfsdk:"directory_path"`
+	LocalWorkspace        types.String `tfsdk:"local_workspace"`
+	Org                   types.String `tfsdk:"org"`
+	TFCWorkspace          types.String `tfsdk:"tfc_workspace"`
+	StateTransformCommand types.List   `tfsdk:"state_transform_command"`
+	MatchingCount         types.Int64  `tfsdk:"matching_count"`
+	MissingCount          types.Int64  `tfsdk:"missing_count"`
+	ExtraCount            types.Int64  `tfsdk:"extra_count"`
+	MissingAddresses      types.List   `tfsdk:"missing_addresses"`
+	ExtraAddresses        types.List   `tfsdk:"extra_addresses"`
+}
+
+// stateResources is the part of a Terraform state needed to list its resource instance addresses.
+type stateResources struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey any `json:"index_key"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// Metadata returns the data source type name.
+func (d *stateParityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_state_parity"
+}
+
+// Schema defines the schema for the data source.
+func (d *stateParityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`tfmigrate_state_parity` compares the resource addresses in the state of a local workspace with the current state of an HCP Terraform workspace, e.g. to audit a migration done with `tfmigrate_state_migration` independently of the migration itself. The provider migrates state into HCP Terraform workspaces, not stack deployments, so the destination workspace is compared. The state of the local workspace is read without selecting it, and `directory_path` must already be initialized, e.g. by `tfmigrate_terraform_init`.",
+		Attributes: map[string]schema.Attribute{
+			"directory_path": schema.StringAttribute{
+				MarkdownDescription: "The directory path where terraform root module is located",
+				Required:            true,
+			},
+			"local_workspace": schema.StringAttribute{
+				MarkdownDescription: "Terraform community workspace name",
+				Required:            true,
+			},
+			"org": schema.StringAttribute{
+				MarkdownDescription: "Organization name of the HCP Terraform workspace.",
+				Required:            true,
+			},
+			"tfc_workspace": schema.StringAttribute{
+				MarkdownDescription: "Terraform cloud workspace name",
+				Required:            true,
+			},
+			"state_transform_command": schema.ListAttribute{
+				MarkdownDescription: "Command and arguments run in `directory_path` to unwrap the state of the local workspace before it is compared, as for `tfmigrate_state_migration`. The pulled state is written to the stdin of the command and its stdout is used as the state.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"matching_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of resource addresses present in both states.",
+				Computed:            true,
+			},
+			"missing_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of resource addresses of the local workspace missing from the HCP Terraform workspace.",
+				Computed:            true,
+			},
+			"extra_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of resource addresses of the HCP Terraform workspace not present in the local workspace.",
+				Computed:            true,
+			},
+			"missing_addresses": schema.ListAttribute{
+				MarkdownDescription: "Resource addresses of the local workspace missing from the HCP Terraform workspace.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"extra_addresses": schema.ListAttribute{
+				MarkdownDescription: "Resource addresses of the HCP Terraform workspace not present in the local workspace.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *stateParityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data stateParityDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dirPath := data.DirectoryPath.ValueString()
+	if _, err := os.Stat(dirPath); err != nil {
+		tflog.Error(ctx, DirPathDoesNotExist)
+		resp.Diagnostics.AddError(DirPathDoesNotExist, fmt.Sprintf(DirPathDoesNotExistDetailed, dirPath))
+		return
+	}
+	// The state is pulled without selecting the workspace, as reading a data source must not change the directory.
+	tfOps := d.newTerraformOperation(dirPath)
+	localState, err := tfOps.StatePullWorkspace(ctx, data.LocalWorkspace.ValueString())
+	if err != nil {
+		tflog.Error(ctx, "Error downloading state ", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error downloading state "+data.LocalWorkspace.ValueString(), err.Error())
+		return
+	}
+	localState, diags := prepareLocalState(ctx, dirPath, data.StateTransformCommand, localState, data.LocalWorkspace.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, tfeOp := newTfeOperation(ctx, d.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	tfeClient, err := newTfeClient(ctx, d.providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing client ", tfeOp.errorDetail(err))
+		return
+	}
+	workspace := data.TFCWorkspace.ValueString()
+	workspaceDetails, err := tfeClient.Workspaces.Read(ctx, data.Org.ValueString(), workspace)
+	if err != nil {
+		tflog.Error(ctx, "Error fetching workspace data "+workspace, map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error fetching workspace data "+workspace, tfeOp.errorDetail(err))
+		return
+	}
+	stateVersion, err := tfeClient.StateVersions.ReadCurrent(ctx, workspaceDetails.ID)
+	if err != nil {
+		tflog.Error(ctx, "Error reading the current state version of workspace "+workspace, map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error reading the current state version of workspace "+workspace, tfeOp.errorDetail(err))
+		return
+	}
+	remoteState, err := tfeClient.StateVersions.Download(ctx, stateVersion.DownloadURL)
+	if err != nil {
+		tflog.Error(ctx, "Error downloading state of workspace "+workspace, map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error downloading state of workspace "+workspace, tfeOp.errorDetail(err))
+		return
+	}
+
+	localAddresses, err := stateResourceAddresses(localState)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing state of workspace "+data.LocalWorkspace.ValueString(), err.Error())
+		return
+	}
+	remoteAddresses, err := stateResourceAddresses(remoteState)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing state of workspace "+workspace, err.Error())
+		return
+	}
+	matching, missing, extra := compareResourceAddresses(localAddresses, remoteAddresses)
+	tflog.Info(ctx, "Compared states", map[string]any{"matching": matching, "missing": len(missing), "extra": len(extra)})
+
+	missingAddresses, diags := types.ListValueFrom(ctx, types.StringType, missing)
+	resp.Diagnostics.Append(diags...)
+	extraAddresses, diags := types.ListValueFrom(ctx, types.StringType, extra)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.MatchingCount = types.Int64Value(int64(matching))
+	data.MissingCount = types.Int64Value(int64(len(missing)))
+	data.ExtraCount = types.Int64Value(int64(len(extra)))
+	data.MissingAddresses = missingAddresses
+	data.ExtraAddresses = extraAddresses
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// stateResourceAddresses returns the addresses of all resource instances of a Terraform state.
+func stateResourceAddresses(state []byte) ([]string, error) {
+	var resources stateResources
+	if err := json.Unmarshal(state, &resources); err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	for _, resource := range resources.Resources {
+		var address strings.Builder
+		if resource.Module != "" {
+			address.WriteString(resource.Module + ".")
+		}
+		if resource.Mode == "data" {
+			address.WriteString("data.")
+		}
+		address.WriteString(resource.Type + "." + resource.Name)
+		for _, instance := range resource.Instances {
+			switch key := instance.IndexKey.(type) {
+			case nil:
+				addresses = append(addresses, address.String())
+			case string:
+				addresses = append(addresses, fmt.Sprintf("%s[%q]", address.String(), key))
+			default:
+				addresses = append(addresses, fmt.Sprintf("%s[%v]", address.String(), key))
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// compareResourceAddresses returns the number of addresses present in both lists, the sorted addresses of local
+// missing from remote and the sorted addresses of remote not present in local.
+func compareResourceAddresses(local []string, remote []string) (int, []string, []string) {
+	remoteSet := make(map[string]bool, len(remote))
+	for _, address := range remote {
+		remoteSet[address] = true
+	}
+
+	matching := 0
+	missing := []string{}
+	for _, address := range local {
+		if remoteSet[address] {
+			matching++
+			delete(remoteSet, address)
+			continue
+		}
+		missing = append(missing, address)
+	}
+	extra := make([]string, 0, len(remoteSet))
+	for address := range remoteSet {
+		extra = append(extra, address)
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return matching, missing, extra
+}
+
+func (d *stateParityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerResourceData, ok := req.ProviderData.(ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Found",
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
+		)
+		return
+	}
+	d.providerResourceData = providerResourceData
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"terraform-provider-tfmigrate/_mocks/terraform_mocks"
+	"terraform-provider-tfmigrate/internal/terraform"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateParity(t *testing.T) {
+	for name, tc := range map[string]struct {
+		localState       string
+		remoteState      string
+		expectedMatching int
+		expectedMissing  []string
+		expectedExtra    []string
+	}{
+		"statesMatch": {
+			localState:       `{"resources": [{"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": [{}]}]}`,
+			remoteState:      `{"resources": [{"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": [{}]}]}`,
+			expectedMatching: 1,
+			expectedMissing:  []string{},
+			expectedExtra:    []string{},
+		},
+		"missingAndExtraInstances": {
+			localState: `{"resources": [
+				{"module": "module.network", "mode": "managed", "type": "aws_subnet", "name": "private", "instances": [{"index_key": 0}, {"index_key": 1}]},
+				{"mode": "data", "type": "aws_region", "name": "current", "instances": [{}]}]}`,
+			remoteState: `{"resources": [
+				{"module": "module.network", "mode": "managed", "type": "aws_subnet", "name": "private", "instances": [{"index_key": 0}]},
+				{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [{"index_key": "blue"}]}]}`,
+			expectedMatching: 1,
+			expectedMissing:  []string{"data.aws_region.current", "module.network.aws_subnet.private[1]"},
+			expectedExtra:    []string{`aws_instance.web["blue"]`},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			local, err := stateResourceAddresses([]byte(tc.localState))
+			r.NoError(err)
+			remote, err := stateResourceAddresses([]byte(tc.remoteState))
+			r.NoError(err)
+
+			// Act
+			matching, missing, extra := compareResourceAddresses(local, remote)
+
+			// Assert
+			r.Equal(tc.expectedMatching, matching)
+			r.Equal(tc.expectedMissing, missing)
+			r.Equal(tc.expectedExtra, extra)
+		})
+	}
+}
+
+func TestStateParityDataSourceRead(t *testing.T) {
+	const localState = `{"version": 4, "serial": 3, "lineage": "8a1f7e6c-7c5a-4d3b-9e2f-0d6b1c2a3e4f", "resources": [
+		{"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": [{}]},
+		{"mode": "managed", "type": "aws_s3_bucket", "name": "assets", "instances": [{}]}]}`
+	const remoteState = `{"version": 4, "serial": 4, "lineage": "8a1f7e6c-7c5a-4d3b-9e2f-0d6b1c2a3e4f", "resources": [
+		{"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": [{}]},
+		{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [{}]}]}`
+	for name, tc := range map[string]struct {
+		pulledState      string
+		transformCommand []string
+		expectedErr      string
+	}{
+		"statesCompared": {
+			pulledState: localState,
+		},
+		"localStateTransformed": {
+			pulledState:      "ENCRYPTED:" + localState,
+			transformCommand: []string{"sed", "s/^ENCRYPTED://"},
+		},
+		"localStateInvalid": {
+			pulledState: "ENCRYPTED:" + localState,
+			expectedErr: StateFormatInvalid,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			var mu sync.Mutex
+			var received []string
+			var server *httptest.Server
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				received = append(received, req.Method+" "+req.URL.Path)
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				switch req.URL.Path {
+				case "/api/v2/organizations/example-org/workspaces/migrated":
+					_, _ = fmt.Fprint(w, `{"data":{"id":"ws-CZcmD7eagjhyX0vN","type":"workspaces","attributes":{"name":"migrated"}}}`)
+				case "/api/v2/workspaces/ws-CZcmD7eagjhyX0vN/current-state-version":
+					_, _ = fmt.Fprintf(w, `{"data":{"id":"sv-CZcmD7eagjhyX0vN","type":"state-versions","attributes":{"hosted-state-download-url":%q}}}`, server.URL+"/state")
+				case "/state":
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = fmt.Fprint(w, remoteState)
+				default:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+			client, err := tfe.NewClient(&tfe.Config{Address: server.URL, Token: "test-token", HTTPClient: server.Client()})
+			r.NoError(err)
+			received = nil
+
+			tfOps := new(terraform_mocks.MockTerraformOperation)
+			tfOps.On("StatePullWorkspace", mock.Anything, "default").Return([]byte(tc.pulledState), nil)
+			dataSource := &stateParityDataSource{
+				providerResourceData:  ProviderResourceData{TfeClientFactory: &fakeTfeClientFactory{client: client}},
+				newTerraformOperation: func(string) terraform.TerraformOperationInterface { return tfOps },
+			}
+			schemaResp := &datasource.SchemaResponse{}
+			dataSource.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+			objectType := schemaResp.Schema.Type().TerraformType(ctx)
+			transformCommand := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil)
+			if tc.transformCommand != nil {
+				var values []tftypes.Value
+				for _, arg := range tc.transformCommand {
+					values = append(values, tftypes.NewValue(tftypes.String, arg))
+				}
+				transformCommand = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values)
+			}
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"directory_path":          tftypes.NewValue(tftypes.String, t.TempDir()),
+				"local_workspace":         tftypes.NewValue(tftypes.String, "default"),
+				"org":                     tftypes.NewValue(tftypes.String, "example-org"),
+				"tfc_workspace":           tftypes.NewValue(tftypes.String, "migrated"),
+				"state_transform_command": transformCommand,
+				"matching_count":          tftypes.NewValue(tftypes.Number, nil),
+				"missing_count":           tftypes.NewValue(tftypes.Number, nil),
+				"extra_count":             tftypes.NewValue(tftypes.Number, nil),
+				"missing_addresses":       tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+				"extra_addresses":         tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+			})}
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+
+			// Act
+			dataSource.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+
+			// Assert
+			tfOps.AssertNotCalled(t, "ExecuteTerraformInit", mock.Anything)
+			tfOps.AssertNotCalled(t, "SelectWorkspace", mock.Anything, mock.Anything)
+			if tc.expectedErr != "" {
+				r.True(resp.Diagnostics.HasError())
+				r.Equal(tc.expectedErr, resp.Diagnostics.Errors()[0].Summary())
+				r.Empty(received)
+				return
+			}
+			r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			var data stateParityDataSourceModel
+			r.False(resp.State.Get(ctx, &data).HasError())
+			r.Equal(int64(1), data.MatchingCount.ValueInt64())
+			var missing, extra []string
+			r.False(data.MissingAddresses.ElementsAs(ctx, &missing, false).HasError())
+			r.False(data.ExtraAddresses.ElementsAs(ctx, &extra, false).HasError())
+			r.Equal([]string{"aws_s3_bucket.assets"}, missing)
+			r.Equal([]string{"aws_instance.web"}, extra)
+		})
+	}
+}
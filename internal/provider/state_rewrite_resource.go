@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"terraform-provider-tfmigrate/internal/terraform"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type stateRewrite struct {
+	newTerraformOperation func(dirPath string) terraform.TerraformOperationInterface
+}
+
+var (
+	_ resource.Resource = &stateRewrite{}
+)
+
+func NewStateRewriteResource() resource.Resource {
+	return &stateRewrite{
+		newTerraformOperation: newTerraformOperation,
+	}
+}
+
+// newTerraformOperation creates the terraform operations run in dirPath.
+func newTerraformOperation(dirPath string) terraform.TerraformOperationInterface {
+	return &terraform.TerraformOperation{
+		DirectoryPath: dirPath,
+	}
+}
+
+type StateRewriteModel struct {
+	DirectoryPath types.String `tfsdk:"directory_path"`
+	Workspace     types.String `tfsdk:"workspace"`
+	AddressMap    types.Map    `tfsdk:"address_map"`
+	Summary       types.String `tfsdk:"summary"`
+}
+
+func (r *stateRewrite) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_state_rewrite"
+}
+
+func (r *stateRewrite) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "State Rewrite Resource: This resource moves resource and module addresses within the state of a workspace, the same way `terraform state mv` does. The state is pulled once, the addresses are moved in a local copy and the result is pushed as a single new state version, so a failed move leaves the state untouched. Use it to refactor a configuration into modules before migrating it.",
+		Attributes: map[string]schema.Attribute{
+			"directory_path": schema.StringAttribute{
+				MarkdownDescription: "The directory path where terraform root module is located. The state is read from and written to the backend configured in this directory.",
+				Required:            true,
+			},
+			"workspace": schema.StringAttribute{
+				MarkdownDescription: "The workspace whose state should be rewritten. Defaults to the currently selected workspace.",
+				Optional:            true,
+			},
+			"address_map": schema.MapAttribute{
+				MarkdownDescription: "Map of current resource or module addresses to their new addresses, e.g. `\"aws_instance.web\" = \"module.web.aws_instance.this\"`. Chained renames such as `a` to `b` and `b` to `c` are rejected; map each address to its final address instead.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"summary": schema.StringAttribute{
+				MarkdownDescription: "Summary of the addresses moved.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *stateRewrite) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var data StateRewriteModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	dirPath := data.DirectoryPath.ValueString()
+	tfOps := r.newTerraformOperation(dirPath)
+	_, err := os.Stat(dirPath)
+	if err != nil {
+		tflog.Error(ctx, DirPathDoesNotExist)
+		resp.Diagnostics.AddError(DirPathDoesNotExist, fmt.Sprintf(DirPathDoesNotExistDetailed, dirPath))
+		return
+	}
+
+	addressMap := make(map[string]string)
+	resp.Diagnostics.Append(data.AddressMap.ElementsAs(ctx, &addressMap, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err = validateAddressMap(addressMap); err != nil {
+		tflog.Error(ctx, StateRewriteInvalidAddressMap, map[string]any{"error": err})
+		resp.Diagnostics.AddError(StateRewriteInvalidAddressMap, err.Error())
+		return
+	}
+
+	err = tfOps.ExecuteTerraformInit(ctx)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing terraform ", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing terraform "+dirPath, err.Error())
+		return
+	}
+
+	if workspace := data.Workspace.ValueString(); workspace != "" {
+		err = tfOps.SelectWorkspace(ctx, workspace)
+		if err != nil {
+			tflog.Error(ctx, "Error selecting workspace ", map[string]any{"error": err})
+			resp.Diagnostics.AddError("Error selecting workspace "+workspace, err.Error())
+			return
+		}
+	}
+
+	if err = rewriteState(ctx, tfOps, r.newTerraformOperation, addressMap); err != nil {
+		tflog.Error(ctx, "Error rewriting state", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error rewriting state of "+dirPath, err.Error())
+		return
+	}
+
+	data.Summary = types.StringValue(fmt.Sprintf(StateRewriteSuccess, len(addressMap)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// validateAddressMap rejects addresses moved to themselves, chained renames and cycles, whose result would depend
+// on the order of the moves, as well as several addresses moved to the same destination.
+func validateAddressMap(addressMap map[string]string) error {
+	sources := sortedAddresses(addressMap)
+	destinations := make(map[string]string, len(addressMap))
+	for _, source := range sources {
+		destination := addressMap[source]
+		if destination == source {
+			return fmt.Errorf("%s is moved to itself", source)
+		}
+		if _, ok := addressMap[destination]; ok {
+			return fmt.Errorf("%s is moved to %s, which is itself moved; chained renames are not supported, move %s to its final address instead", source, destination, source)
+		}
+		if other, ok := destinations[destination]; ok {
+			return fmt.Errorf("%s and %s are both moved to %s", other, source, destination)
+		}
+		destinations[destination] = source
+	}
+	return nil
+}
+
+// rewriteState pulls the state through tfOps once, moves the addresses in a local copy of it and pushes the result
+// as a single state version, so a failed move leaves the state of the backend untouched.
+func rewriteState(ctx context.Context, tfOps terraform.TerraformOperationInterface, newTerraformOperation func(dirPath string) terraform.TerraformOperationInterface, addressMap map[string]string) error {
+	state, err := tfOps.StatePull(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to pull state: %w", err)
+	}
+	if err = validateStateFormat(state); err != nil {
+		return fmt.Errorf("failed to read the pulled state: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "tfmigrate-state-rewrite-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	// The copy is moved in a directory without configuration, so terraform uses the local terraform.tfstate file.
+	statePath := filepath.Join(tmpDir, "terraform.tfstate")
+	if err = os.WriteFile(statePath, state, 0600); err != nil {
+		return err
+	}
+	localOps := newTerraformOperation(tmpDir)
+	for _, source := range sortedAddresses(addressMap) {
+		destination := addressMap[source]
+		tflog.Info(ctx, "Moving state address", map[string]any{"source": source, "destination": destination})
+		if err = localOps.StateMv(ctx, source, destination); err != nil {
+			return fmt.Errorf("failed to move %s to %s, the state was not changed: %w", source, destination, err)
+		}
+	}
+
+	if err = tfOps.StatePush(ctx, statePath); err != nil {
+		return fmt.Errorf("failed to push the rewritten state: %w", err)
+	}
+	return nil
+}
+
+// sortedAddresses returns the source addresses of addressMap in a stable order, so that a failure is reproducible.
+func sortedAddresses(addressMap map[string]string) []string {
+	sources := make([]string, 0, len(addressMap))
+	for source := range addressMap {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (r *stateRewrite) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *stateRewrite) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data StateRewriteModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.AddWarning(UpdateActionNotSupported, UpdateActionNotSupportedDetailed)
+	data.Summary = types.StringValue(UpdateActionNotSupported)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *stateRewrite) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Warn(ctx, DestroyActionNotSupported)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"terraform-provider-tfmigrate/_mocks/terraform_mocks"
+	"terraform-provider-tfmigrate/internal/terraform"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	invalidStateRewriteTestDir = `/Some/Invalid/Path`
+)
+
+func TestPathOnStateRewriteResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      getStateRewriteConfigsForDirPath(invalidStateRewriteTestDir),
+				ExpectError: regexp.MustCompile(DirPathDoesNotExist),
+			},
+		},
+	})
+}
+
+func getStateRewriteConfigsForDirPath(directory string) string {
+	return fmt.Sprintf(providerConfig+`
+resource "tfmigrate_state_rewrite" "test" {
+	directory_path = %[1]q
+	address_map = {
+		"random_shuffle.az" = "module.az.random_shuffle.this"
+	}
+}
+`, directory)
+}
+
+func TestValidateAddressMap(t *testing.T) {
+	for name, tc := range map[string]struct {
+		addressMap  map[string]string
+		expectedErr string
+	}{
+		"independentMoves": {
+			addressMap: map[string]string{"aws_instance.web": "module.web.aws_instance.this", "module.network_old": "module.network"},
+		},
+		"chainedRename": {
+			addressMap:  map[string]string{"a.x": "a.y", "a.y": "a.z"},
+			expectedErr: "a.x is moved to a.y, which is itself moved; chained renames are not supported, move a.x to its final address instead",
+		},
+		"cycle": {
+			addressMap:  map[string]string{"a.x": "a.y", "a.y": "a.x"},
+			expectedErr: "a.x is moved to a.y, which is itself moved; chained renames are not supported, move a.x to its final address instead",
+		},
+		"movedToItself": {
+			addressMap:  map[string]string{"a.x": "a.x"},
+			expectedErr: "a.x is moved to itself",
+		},
+		"sameDestination": {
+			addressMap:  map[string]string{"a.x": "a.z", "a.y": "a.z"},
+			expectedErr: "a.x and a.y are both moved to a.z",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			err := validateAddressMap(tc.addressMap)
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+		})
+	}
+}
+
+func TestRewriteState(t *testing.T) {
+	const state = `{"version": 4, "serial": 3, "lineage": "8a1f7e6c-7c5a-4d3b-9e2f-0d6b1c2a3e4f", "resources": []}`
+	for name, tc := range map[string]struct {
+		stateMvErrs   map[string]error
+		statePushErr  error
+		expectedMoves []string
+		expectPush    bool
+		expectedErr   string
+	}{
+		"movedAndPushedOnce": {
+			expectedMoves: []string{"aws_instance.web", "module.network_old"},
+			expectPush:    true,
+		},
+		"moveFailedNothingPushed": {
+			stateMvErrs:   map[string]error{"aws_instance.web": errors.New("no matching objects found")},
+			expectedMoves: []string{"aws_instance.web"},
+			expectedErr:   "failed to move aws_instance.web to module.web.aws_instance.this, the state was not changed: no matching objects found",
+		},
+		"pushFailed": {
+			statePushErr:  errors.New("state lineage mismatch"),
+			expectedMoves: []string{"aws_instance.web", "module.network_old"},
+			expectPush:    true,
+			expectedErr:   "failed to push the rewritten state: state lineage mismatch",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			addressMap := map[string]string{
+				"module.network_old": "module.network",
+				"aws_instance.web":   "module.web.aws_instance.this",
+			}
+			tfOps := new(terraform_mocks.MockTerraformOperation)
+			tfOps.On("StatePull", ctx).Return([]byte(state), nil)
+			tfOps.On("StatePush", ctx, mock.AnythingOfType("string")).Return(tc.statePushErr)
+
+			var localDir string
+			var moves []string
+			localOps := new(terraform_mocks.MockTerraformOperation)
+			for source, destination := range addressMap {
+				localOps.On("StateMv", ctx, source, destination).
+					Run(func(_ mock.Arguments) {
+						copied, err := os.ReadFile(filepath.Join(localDir, "terraform.tfstate"))
+						r.NoError(err)
+						r.Equal(state, string(copied), "moves must be applied to the local copy of the pulled state")
+						moves = append(moves, source)
+					}).
+					Return(tc.stateMvErrs[source])
+			}
+			newLocalOps := func(dirPath string) terraform.TerraformOperationInterface {
+				localDir = dirPath
+				return localOps
+			}
+
+			// Act
+			err := rewriteState(ctx, tfOps, newLocalOps, addressMap)
+
+			// Assert
+			r.Equal(tc.expectedMoves, moves)
+			tfOps.AssertNumberOfCalls(t, "StatePull", 1)
+			if tc.expectPush {
+				tfOps.AssertNumberOfCalls(t, "StatePush", 1)
+				r.Equal(filepath.Join(localDir, "terraform.tfstate"), tfOps.Calls[1].Arguments.Get(1))
+			} else {
+				tfOps.AssertNotCalled(t, "StatePush", ctx, mock.Anything)
+			}
+			r.NoDirExists(localDir, "the local copy must be removed")
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+		})
+	}
+}
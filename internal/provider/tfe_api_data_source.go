@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &tfeApiDataSource{}
+	_ datasource.DataSourceWithConfigure = &tfeApiDataSource{}
+)
+
+// NewTfeApiDataSource is a helper function to simplify the provider implementation.
+func NewTfeApiDataSource() datasource.DataSource {
+	return &tfeApiDataSource{}
+}
+
+// tfeApiDataSource is the data source implementation.
+type tfeApiDataSource struct {
+	providerResourceData ProviderResourceData
+}
+
+// tfeApiDataSourceModel describes the data source data model.
+type tfeApiDataSourceModel struct {
+	Hostname         types.String `tfsdk:"hostname"`
+	BaseUrl          types.String `tfsdk:"base_url"`
+	RegistryBaseUrl  types.String `tfsdk:"registry_base_url"`
+	RemoteApiVersion types.String `tfsdk:"remote_api_version"`
+	RemoteTfeVersion types.String `tfsdk:"remote_tfe_version"`
+	AppName          types.String `tfsdk:"app_name"`
+}
+
+// Metadata returns the data source type name.
+func (d *tfeApiDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tfe_api"
+}
+
+// Schema defines the schema for the data source.
+func (d *tfeApiDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`tfmigrate_tfe_api` connects to the TFE instance configured on the provider and exposes the resolved API endpoints. Use it to debug proxy and base path issues before running a migration.",
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "The hostname of the TFE instance, as configured on the provider.",
+				Computed:            true,
+			},
+			"base_url": schema.StringAttribute{
+				MarkdownDescription: "The resolved base URL of the TFE API.",
+				Computed:            true,
+			},
+			"registry_base_url": schema.StringAttribute{
+				MarkdownDescription: "The resolved base URL of the private registry API.",
+				Computed:            true,
+			},
+			"remote_api_version": schema.StringAttribute{
+				MarkdownDescription: "The API version reported by the TFE instance.",
+				Computed:            true,
+			},
+			"remote_tfe_version": schema.StringAttribute{
+				MarkdownDescription: "The TFE release reported by the instance. Empty for HCP Terraform.",
+				Computed:            true,
+			},
+			"app_name": schema.StringAttribute{
+				MarkdownDescription: "The application name reported by the instance, e.g. `HCP Terraform` or `Terraform Enterprise`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *tfeApiDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, tfeOp := newTfeOperation(ctx, d.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	// Creating the client fetches the API discovery metadata, which also validates connectivity.
+	tfeClient, err := newTfeClient(ctx, d.providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing client ", tfeOp.errorDetail(err))
+		return
+	}
+
+	baseUrl := tfeClient.BaseURL()
+	registryBaseUrl := tfeClient.BaseRegistryURL()
+	data := tfeApiDataSourceModel{
+		Hostname:         types.StringValue(d.providerResourceData.Hostname),
+		BaseUrl:          types.StringValue(baseUrl.String()),
+		RegistryBaseUrl:  types.StringValue(registryBaseUrl.String()),
+		RemoteApiVersion: types.StringValue(tfeClient.RemoteAPIVersion()),
+		RemoteTfeVersion: types.StringValue(tfeClient.RemoteTFEVersion()),
+		AppName:          types.StringValue(tfeClient.AppName()),
+	}
+	tflog.Info(ctx, "Resolved TFE API endpoints", map[string]any{"baseUrl": baseUrl.String(), "apiVersion": tfeClient.RemoteAPIVersion()})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *tfeApiDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerResourceData, ok := req.ProviderData.(ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Found",
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
+		)
+		return
+	}
+	d.providerResourceData = providerResourceData
+}
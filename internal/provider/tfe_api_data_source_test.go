@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTfeApiDataSourceRead(t *testing.T) {
+	for name, tc := range map[string]struct {
+		factoryErr         error
+		expectedApiVersion string
+		expectedErr        string
+	}{
+		"endpointsResolved": {
+			expectedApiVersion: "2.6",
+		},
+		"clientCreationFailed": {
+			factoryErr:  errors.New("connection refused"),
+			expectedErr: "Error initializing client ",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			var client *tfe.Client
+			if tc.factoryErr == nil {
+				client = newTestTfeClient(t, tc.expectedApiVersion)
+			}
+			d := &tfeApiDataSource{providerResourceData: ProviderResourceData{
+				Hostname:         "tfe.example.com",
+				TfeClientFactory: &fakeTfeClientFactory{client: client, err: tc.factoryErr},
+			}}
+			schemaResp := &datasource.SchemaResponse{}
+			d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+			resp := &datasource.ReadResponse{State: tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}}
+
+			// Act
+			d.Read(ctx, datasource.ReadRequest{}, resp)
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.True(resp.Diagnostics.HasError())
+				r.Equal(tc.expectedErr, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			var data tfeApiDataSourceModel
+			r.False(resp.State.Get(ctx, &data).HasError())
+			r.Equal("tfe.example.com", data.Hostname.ValueString())
+			baseUrl := client.BaseURL()
+			r.Equal(baseUrl.String(), data.BaseUrl.ValueString())
+			r.Equal(tc.expectedApiVersion, data.RemoteApiVersion.ValueString())
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	httpUtil "terraform-provider-tfmigrate/internal/util/http"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// TfeClientFactory is the factory interface for creating the TFE clients used by resources and data sources.
+// Tools embedding the provider, and tests, can supply their own implementation through WithTfeClientFactory.
+type TfeClientFactory interface {
+	NewTfeClient(ctx context.Context, providerResourceData ProviderResourceData) (*tfe.Client, error)
+}
+
+// tfeClientFactory implements TfeClientFactory.
+type tfeClientFactory struct {
+	httpClient *http.Client
+}
+
+// NewTfeClientFactory creates a new instance of TfeClientFactory.
+// If httpClient is nil, a client with a default transport is created for every TFE client.
+func NewTfeClientFactory(httpClient *http.Client) TfeClientFactory {
+	return &tfeClientFactory{
+		httpClient: httpClient,
+	}
+}
+
+// NewTfeClient creates a TFE client for the hostname configured on the provider.
+// The extra_headers, debug_http and max_api_calls settings are applied on top of the transport of the http client.
+// Without an injected http client, the TLS certificate of the TFE instance is verified unless
+// tls_insecure_skip_verify is set.
+func (f *tfeClientFactory) NewTfeClient(ctx context.Context, providerResourceData ProviderResourceData) (*tfe.Client, error) {
+	hostname := providerResourceData.Hostname
+
+	client := &http.Client{}
+	var tr http.RoundTripper
+	if f.httpClient != nil {
+		*client = *f.httpClient
+		tr = f.httpClient.Transport
+	} else if providerResourceData.TlsInsecure {
+		tflog.Warn(ctx, "tls_insecure_skip_verify is set, the TLS certificate of the TFE instance is not verified", map[string]any{"hostname": hostname})
+		tr = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	tr = httpUtil.NewRequestIdRoundTripper(tr)
+	tr = httpUtil.NewApiCallCounterRoundTripper(tr)
+	if providerResourceData.DebugHttp {
+		tr = httpUtil.NewDebugRoundTripper(tr)
+	}
+	tr = httpUtil.NewHeaderRoundTripper(tr, providerResourceData.ExtraHeaders)
+	client.Transport = httpUtil.NewDefaultContextRoundTripper(ctx, tr)
+
+	token, err := tfeUtil.ReadTfeToken(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	tfcConfig := &tfe.Config{
+		Address:           TfcScheme + "://" + hostname + "/",
+		Token:             token,
+		RetryServerErrors: true,
+		RetryLogHook:      newTfeRetryLogHook(ctx),
+		HTTPClient:        client,
+	}
+	return tfe.NewClient(tfcConfig)
+}
+
+// newTfeClient creates a TFE client using the factory configured on the provider.
+func newTfeClient(ctx context.Context, providerResourceData ProviderResourceData) (*tfe.Client, error) {
+	factory := providerResourceData.TfeClientFactory
+	if factory == nil {
+		factory = NewTfeClientFactory(nil)
+	}
+	return factory.NewTfeClient(ctx, providerResourceData)
+}
+
+// tfeOperation tracks the TFE API requests made by a single resource or data source operation.
+type tfeOperation struct {
+	maxApiCalls int
+	requestIds  *httpUtil.RequestIdRecorder
+	apiCalls    *httpUtil.ApiCallCounter
+	// callStart is the number of request IDs recorded before the current call, see startCall.
+	callStart int
+}
+
+// newTfeOperation returns a context tracking the TFE API requests made with it. The requests are limited to the
+// max_api_calls configured on the provider. The context is released by close.
+func newTfeOperation(ctx context.Context, providerResourceData ProviderResourceData) (context.Context, *tfeOperation) {
+	ctx, requestIds := httpUtil.WithRequestIdRecorder(ctx)
+	ctx, apiCalls := httpUtil.WithApiCallCounter(ctx, providerResourceData.MaxApiCalls)
+	return ctx, &tfeOperation{
+		maxApiCalls: providerResourceData.MaxApiCalls,
+		requestIds:  requestIds,
+		apiCalls:    apiCalls,
+	}
+}
+
+// startCall marks the start of a TFE API call whose failure is reported on its own, e.g. one call of a loop,
+// so that errorDetail only includes the request IDs of the requests made since.
+func (o *tfeOperation) startCall() {
+	o.callStart = len(o.requestIds.RequestIds())
+}
+
+// errorDetail returns the detail of the diagnostic for a failed TFE API call. The IDs of the failed requests
+// made since the start of the operation, or since the last startCall, are included so the server side logs
+// can be looked up.
+func (o *tfeOperation) errorDetail(err error) string {
+	detail := err.Error()
+	if o.apiCalls.Exceeded() {
+		detail = fmt.Sprintf(MaxApiCallsExceededDetailed, o.maxApiCalls) + "\n\n" + detail
+	}
+	if ids := o.requestIds.RequestIds()[o.callStart:]; len(ids) > 0 {
+		detail = fmt.Sprintf("%s\n\nTFE request ID(s): %s", detail, strings.Join(ids, ", "))
+	}
+	return detail
+}
+
+// close releases the context of the operation. It must be deferred once the operation is created.
+func (o *tfeOperation) close() {
+	o.apiCalls.Stop()
+}
+
+// logApiCalls logs the number of TFE API requests made, per endpoint.
+func (o *tfeOperation) logApiCalls(ctx context.Context) {
+	tflog.Debug(ctx, "TFE API calls", map[string]any{"total": o.apiCalls.Total(), "calls": o.apiCalls.Calls()})
+}
+
+// newTfeRetryLogHook logs every retry done by the TFE client, so transient failures during
+// long running calls such as state uploads are visible instead of looking like a hang.
+// go-retryablehttp calls the hook with attemptNum 0 before the first retry, so the retry number is attemptNum+1.
+func newTfeRetryLogHook(ctx context.Context) tfe.RetryLogHook {
+	return func(attemptNum int, resp *http.Response) {
+		fields := map[string]any{"retry": attemptNum + 1}
+		if resp != nil {
+			fields["status"] = resp.StatusCode
+			if resp.Request != nil {
+				fields["method"] = resp.Request.Method
+				fields["url"] = httpUtil.SanitizeUrl(resp.Request.URL)
+			}
+		}
+		tflog.Warn(ctx, "Retrying TFE API request", fields)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	netMock "terraform-provider-tfmigrate/_mocks/net_mocks"
+	httpUtil "terraform-provider-tfmigrate/internal/util/http"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTfeClientFactory struct {
+	client *tfe.Client
+	err    error
+	calls  int
+}
+
+func (f *fakeTfeClientFactory) NewTfeClient(_ context.Context, _ ProviderResourceData) (*tfe.Client, error) {
+	f.calls++
+	return f.client, f.err
+}
+
+// newTestTfeClient returns a TFE client for a test server reporting the given API version.
+func newTestTfeClient(t *testing.T, apiVersion string) *tfe.Client {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if apiVersion != "" {
+			w.Header().Set("TFP-API-Version", apiVersion)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tfe.NewClient(&tfe.Config{
+		Address:    server.URL,
+		Token:      "test-token",
+		HTTPClient: server.Client(),
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewTfeClient(t *testing.T) {
+	for name, tc := range map[string]struct {
+		extraHeaders    map[string]string
+		injectedFactory bool
+		expectedErr     string
+	}{
+		"httpClientInjected": {},
+		"extraHeadersAppliedToInjectedHttpClient": {
+			extraHeaders: map[string]string{"X-Tenant-Id": "tenant-1"},
+		},
+		"tfeClientFactoryInjected": {
+			injectedFactory: true,
+			expectedErr:     "fake client factory",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			var receivedHeaders http.Header
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				receivedHeaders = req.Header.Clone()
+				w.Header().Set("TFP-API-Version", "2.6")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			serverUrl, err := url.Parse(server.URL)
+			r.NoError(err)
+			t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+
+			var opts []Option
+			if tc.injectedFactory {
+				opts = append(opts, WithTfeClientFactory(&fakeTfeClientFactory{err: errors.New("fake client factory")}))
+			} else {
+				opts = append(opts, WithHttpClient(server.Client()))
+			}
+			p, ok := New("test", opts...)().(*tfmProvider)
+			r.True(ok)
+
+			// Act
+			client, err := newTfeClient(context.Background(), ProviderResourceData{
+				Hostname:         serverUrl.Host,
+				ExtraHeaders:     tc.extraHeaders,
+				TfeClientFactory: p.tfeClientFactory,
+			})
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal("2.6", client.RemoteAPIVersion())
+			r.Equal("Bearer test-token", receivedHeaders.Get("Authorization"))
+			for k, v := range tc.extraHeaders {
+				r.Equal(v, receivedHeaders.Get(k))
+			}
+		})
+	}
+}
+
+func TestNewTfeClientVerifiesTls(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tlsInsecure bool
+		expectedErr string
+	}{
+		"untrustedCertificateRejected": {
+			expectedErr: "certificate signed by unknown authority",
+		},
+		"verificationSkippedWhenOptedIn": {
+			tlsInsecure: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("TFP-API-Version", "2.6")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+			serverUrl, err := url.Parse(server.URL)
+			r.NoError(err)
+			t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+
+			// Act
+			client, err := NewTfeClientFactory(nil).NewTfeClient(context.Background(), ProviderResourceData{
+				Hostname:    serverUrl.Host,
+				TlsInsecure: tc.tlsInsecure,
+			})
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.ErrorContains(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal("2.6", client.RemoteAPIVersion())
+		})
+	}
+}
+
+func TestTfeOperationErrorDetail(t *testing.T) {
+	for name, tc := range map[string]struct {
+		requestIds     []string
+		maxApiCalls    int
+		startCallAfter int
+		expectedDetail string
+	}{
+		"noRequestIds": {
+			expectedDetail: "resource not found",
+		},
+		"requestIdsAppended": {
+			requestIds:     []string{"req-1", "req-2"},
+			expectedDetail: "resource not found\n\nTFE request ID(s): req-1, req-2",
+		},
+		"maxApiCallsExceeded": {
+			requestIds:     []string{"req-1", "req-2"},
+			maxApiCalls:    1,
+			expectedDetail: "The operation made more than 1 TFE API calls, the limit set by max_api_calls.\n\nresource not found\n\nTFE request ID(s): req-1",
+		},
+		"onlyRequestIdsSinceStartCall": {
+			requestIds:     []string{"req-1", "req-2", "req-3"},
+			startCallAfter: 2,
+			expectedDetail: "resource not found\n\nTFE request ID(s): req-3",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			mockTransport := new(netMock.MockRoundTripper)
+			for _, requestId := range tc.requestIds {
+				mockTransport.
+					On("RoundTrip", mock.AnythingOfType("*http.Request")).
+					Return(&http.Response{StatusCode: http.StatusNotFound, Header: http.Header{httpUtil.RequestIdHeader: []string{requestId}}}, nil).
+					Once()
+			}
+			ctx, tfeOp := newTfeOperation(context.Background(), ProviderResourceData{MaxApiCalls: tc.maxApiCalls})
+			defer tfeOp.close()
+			roundTripper := httpUtil.NewApiCallCounterRoundTripper(httpUtil.NewRequestIdRoundTripper(mockTransport))
+			for i := range tc.requestIds {
+				if i == tc.startCallAfter {
+					tfeOp.startCall()
+				}
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://app.terraform.io/api/v2/ping", nil)
+				r.NoError(err)
+				_, _ = roundTripper.RoundTrip(req)
+			}
+
+			// Act
+			detail := tfeOp.errorDetail(errors.New("resource not found"))
+
+			// Assert
+			r.Equal(tc.expectedDetail, detail)
+		})
+	}
+}
+
+func TestNewTfeClientPingTrackedByOperation(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(httpUtil.RequestIdHeader, "req-ping")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	serverUrl, err := url.Parse(server.URL)
+	r.NoError(err)
+	t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+	providerResourceData := ProviderResourceData{
+		Hostname:         serverUrl.Host,
+		TfeClientFactory: NewTfeClientFactory(server.Client()),
+	}
+	ctx, tfeOp := newTfeOperation(context.Background(), providerResourceData)
+	defer tfeOp.close()
+
+	// Act
+	_, err = newTfeClient(ctx, providerResourceData)
+
+	// Assert
+	r.NoError(err)
+	r.Equal(map[string]int{"GET /api/v2/ping": 1}, tfeOp.apiCalls.Calls())
+	r.Equal("service unavailable\n\nTFE request ID(s): req-ping", tfeOp.errorDetail(errors.New("service unavailable")))
+}
+
+func TestTfeRetryLogHook(t *testing.T) {
+	for name, tc := range map[string]struct {
+		attemptNum     int
+		resp           *http.Response
+		expectedFields map[string]any
+	}{
+		"firstRetryLogged": {
+			attemptNum: 0,
+			resp: &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{Scheme: "https", Host: "archivist.terraform.io", Path: "/v1/object/" + strings.Repeat("a", 120)}},
+			},
+			expectedFields: map[string]any{
+				"retry":  float64(1),
+				"status": float64(http.StatusBadGateway),
+				"method": http.MethodPut,
+				"url":    "https://archivist.terraform.io/v1/object/REDACTED",
+			},
+		},
+		"retryWithoutResponse": {
+			attemptNum:     2,
+			expectedFields: map[string]any{"retry": float64(3)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+
+			// Act
+			newTfeRetryLogHook(ctx)(tc.attemptNum, tc.resp)
+
+			// Assert
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			r.NoError(err)
+			r.Len(entries, 1)
+			r.Equal("warn", entries[0]["@level"])
+			r.Equal("Retrying TFE API request", entries[0]["@message"])
+			for k, v := range tc.expectedFields {
+				r.Equal(v, entries[0][k], k)
+			}
+		})
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -31,17 +34,18 @@ func NewDirectoryActionResource() resource.Resource {
 
 // directoryActions is the resource implementation.
 type directoryActions struct {
-	Hostname string
+	providerResourceData ProviderResourceData
 }
 
 // DirectoryActionResourceModel describes the resource data model.
 type DirectoryActionResourceModel struct {
-	Org           types.String `tfsdk:"org"`
-	Project       types.String `tfsdk:"project"`
-	DirectoryPath types.String `tfsdk:"directory_path"`
-	BackendFile   types.String `tfsdk:"backend_file_name"`
-	WorkspaceMap  types.Map    `tfsdk:"workspace_map"`
-	Tags          types.List   `tfsdk:"tags"`
+	Org               types.String `tfsdk:"org"`
+	Project           types.String `tfsdk:"project"`
+	UseDefaultProject types.Bool   `tfsdk:"use_default_project"`
+	DirectoryPath     types.String `tfsdk:"directory_path"`
+	BackendFile       types.String `tfsdk:"backend_file_name"`
+	WorkspaceMap      types.Map    `tfsdk:"workspace_map"`
+	Tags              types.List   `tfsdk:"tags"`
 }
 
 // Metadata returns the resource type name.
@@ -69,8 +73,12 @@ func (r *directoryActions) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Required:            true,
 			},
 			"project": schema.StringAttribute{
-				MarkdownDescription: "Project Name required in the cloud block.",
-				Required:            true,
+				MarkdownDescription: "Project Name required in the cloud block. Defaults to the `TFE_PROJECT` environment variable.",
+				Optional:            true,
+			},
+			"use_default_project": schema.BoolAttribute{
+				MarkdownDescription: "Use the default project of the organization when `project` is not set and `TFE_PROJECT` is unset.",
+				Optional:            true,
 			},
 			"workspace_map": schema.MapAttribute{
 				MarkdownDescription: "Terraform cloud workspace to local workspace mapping.",
@@ -95,9 +103,19 @@ func (r *directoryActions) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	ctx, tfeOp := newTfeOperation(ctx, r.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	project, err := r.resolveProject(ctx, data)
+	if err != nil {
+		tflog.Error(ctx, "[TFM] ERROR while resolving project", map[string]any{"error": err})
+		resp.Diagnostics.AddError("ERROR while resolving project", tfeOp.errorDetail(err))
+		return
+	}
+
 	RemoveBackendBlock(ctx, data.DirectoryPath.ValueString(), data.BackendFile.ValueString(), resp)
 	tflog.Trace(ctx, "Completed Removing backend block.")
-	AddCloudBlock(ctx, data, data.BackendFile.ValueString(), r.Hostname, resp)
+	AddCloudBlock(ctx, data, project, data.BackendFile.ValueString(), r.providerResourceData.Hostname, resp)
 	tflog.Trace(ctx, "Completed Appending a cloud block.")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -123,6 +141,36 @@ func (r *directoryActions) Update(ctx context.Context, req resource.UpdateReques
 func (r *directoryActions) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 }
 
+// resolveProject returns the project to use in the cloud block. The project attribute takes precedence over
+// the TFE_PROJECT environment variable, which takes precedence over the default project of the organization.
+func (r *directoryActions) resolveProject(ctx context.Context, data DirectoryActionResourceModel) (string, error) {
+	if project := data.Project.ValueString(); project != "" {
+		return project, nil
+	}
+	if project := os.Getenv(TfeProjectEnvName); project != "" {
+		return project, nil
+	}
+	if !data.UseDefaultProject.ValueBool() {
+		return "", fmt.Errorf("no project specified, set the project attribute or the %s environment variable, or set use_default_project = true to use the default project of the organization", TfeProjectEnvName)
+	}
+
+	tfeClient, err := newTfeClient(ctx, r.providerResourceData)
+	if err != nil {
+		return "", err
+	}
+	org, err := tfeClient.Organizations.ReadWithOptions(ctx, data.Org.ValueString(), tfe.OrganizationReadOptions{
+		Include: []tfe.OrganizationIncludeOpt{tfe.OrganizationDefaultProject},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read organization %s: %w", data.Org.ValueString(), err)
+	}
+	if org.DefaultProject == nil || org.DefaultProject.Name == "" {
+		return "", fmt.Errorf("organization %s has no default project", data.Org.ValueString())
+	}
+	tflog.Info(ctx, "[TFM] Using default project of the organization", map[string]any{"org": data.Org.ValueString(), "project": org.DefaultProject.Name})
+	return org.DefaultProject.Name, nil
+}
+
 func RemoveBackendBlock(ctx context.Context, dirPath string, backendFile string, resp *resource.CreateResponse) {
 	tflog.Info(ctx, "[TFM] Removing backend block")
 	filePath := dirPath + "/" + backendFile
@@ -135,7 +183,7 @@ func RemoveBackendBlock(ctx context.Context, dirPath string, backendFile string,
 	file, diags := hclwrite.ParseConfig(content, filePath, hcl.Pos{Line: 1, Column: 1})
 	if diags.HasErrors() {
 		tflog.Error(ctx, "[TFM] ERROR while parsing terraform config", map[string]any{"error": diags.Error()})
-		resp.Diagnostics.AddError("ERROR while parsing terraform config", " Error "+diags.Error())
+		resp.Diagnostics.Append(hclDiagnosticsToTfDiagnostics(diags, content)...)
 		return
 	}
 	for _, block := range file.Body().Blocks() {
@@ -154,7 +202,7 @@ func RemoveBackendBlock(ctx context.Context, dirPath string, backendFile string,
 	}
 }
 
-func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, backendFile string, hostname string, resp *resource.CreateResponse) {
+func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, project string, backendFile string, hostname string, resp *resource.CreateResponse) {
 	tflog.Info(ctx, "[TFM] Adding cloud block")
 	filePath := data.DirectoryPath.ValueString() + "/" + backendFile
 	content, err := os.ReadFile(filePath)
@@ -166,7 +214,7 @@ func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, backe
 	file, diags := hclwrite.ParseConfig(content, filePath, hcl.Pos{Line: 1, Column: 1})
 	if diags.HasErrors() {
 		tflog.Error(ctx, "[TFM] ERROR while parsing terraform config", map[string]any{"error": diags.Error()})
-		resp.Diagnostics.AddError("ERROR while parsing terraform config", " Error "+diags.Error())
+		resp.Diagnostics.Append(hclDiagnosticsToTfDiagnostics(diags, content)...)
 		return
 	}
 	for _, block := range file.Body().Blocks() {
@@ -201,7 +249,7 @@ func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, backe
 					return
 				}
 				workspacesBlock := cloudBlock.Body().AppendNewBlock("workspaces", nil)
-				workspacesBlock.Body().SetAttributeValue("project", cty.StringVal(data.Project.ValueString()))
+				workspacesBlock.Body().SetAttributeValue("project", cty.StringVal(project))
 				workspacesBlock.Body().SetAttributeValue("name", cty.StringVal(workspace))
 			}
 			//----- multiple workspaces will write tags
@@ -223,7 +271,7 @@ func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, backe
 					tags[i] = cty.StringVal(tag)
 				}
 				workspacesBlock := cloudBlock.Body().AppendNewBlock("workspaces", nil)
-				workspacesBlock.Body().SetAttributeValue("project", cty.StringVal(data.Project.ValueString()))
+				workspacesBlock.Body().SetAttributeValue("project", cty.StringVal(project))
 				workspacesBlock.Body().SetAttributeValue("tags", cty.ListVal(tags))
 			}
 			break
@@ -236,6 +284,30 @@ func AddCloudBlock(ctx context.Context, data DirectoryActionResourceModel, backe
 	}
 }
 
+// hclDiagnosticsToTfDiagnostics converts HCL parse diagnostics into Terraform diagnostics,
+// keeping the file name, position and offending source line of each diagnostic.
+func hclDiagnosticsToTfDiagnostics(hclDiags hcl.Diagnostics, src []byte) diag.Diagnostics {
+	var diags diag.Diagnostics
+	lines := strings.Split(string(src), "\n")
+	for _, hclDiag := range hclDiags {
+		detail := hclDiag.Detail
+		if subject := hclDiag.Subject; subject != nil {
+			position := fmt.Sprintf("on %s line %d, column %d", subject.Filename, subject.Start.Line, subject.Start.Column)
+			if subject.Start.Line > 0 && subject.Start.Line <= len(lines) {
+				position += fmt.Sprintf(":\n%4d: %s", subject.Start.Line, lines[subject.Start.Line-1])
+			}
+			detail = position + "\n\n" + detail
+		}
+		summary := "ERROR while parsing terraform config: " + hclDiag.Summary
+		if hclDiag.Severity == hcl.DiagError {
+			diags.AddError(summary, detail)
+		} else {
+			diags.AddWarning(summary, detail)
+		}
+	}
+	return diags
+}
+
 func getTFCWorkspace(ctx context.Context, m basetypes.MapValue, resp *resource.CreateResponse) (string, bool) {
 	workspace := ""
 	for _, v := range m.Elements() {
@@ -266,10 +338,10 @@ func (r *directoryActions) Configure(_ context.Context, req resource.ConfigureRe
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Github Token Found",
-			fmt.Sprintf("providerResourceData from context is %s.", providerResourceData),
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
 		)
 
 		return
 	}
-	r.Hostname = providerResourceData.Hostname
+	r.providerResourceData = providerResourceData
 }
@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	tfeUtil "terraform-provider-tfmigrate/internal/util/tfe"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHclDiagnosticsToTfDiagnostics(t *testing.T) {
+	for name, tc := range map[string]struct {
+		src            string
+		errorCount     int
+		detailContains []string
+	}{
+		"validConfig": {
+			src: "terraform {\n  backend \"s3\" {}\n}\n",
+		},
+		"missingClosingBrace": {
+			src:            "terraform {\n  backend \"s3\" {\n}\n",
+			errorCount:     1,
+			detailContains: []string{"on main.tf line"},
+		},
+		"invalidAttribute": {
+			src:            "terraform {\n  required_version = \n}\n",
+			errorCount:     1,
+			detailContains: []string{"on main.tf line 2, column", "   2:   required_version = "},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			_, hclDiags := hclwrite.ParseConfig([]byte(tc.src), "main.tf", hcl.Pos{Line: 1, Column: 1})
+
+			// Act
+			diags := hclDiagnosticsToTfDiagnostics(hclDiags, []byte(tc.src))
+
+			// Assert
+			r.Equal(tc.errorCount, diags.ErrorsCount())
+			for _, contains := range tc.detailContains {
+				r.Contains(joinDetails(diags), contains)
+			}
+		})
+	}
+}
+
+func joinDetails(diags diag.Diagnostics) string {
+	var details []string
+	for _, d := range diags {
+		details = append(details, d.Detail())
+	}
+	return strings.Join(details, "\n")
+}
+
+func TestUpdateBackendProjectOptional(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	schemaResp := &resource.SchemaResponse{}
+
+	// Act
+	(&directoryActions{}).Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+
+	// Assert
+	project := schemaResp.Schema.Attributes["project"]
+	r.True(project.IsOptional())
+	r.False(project.IsRequired())
+	r.True(schemaResp.Schema.Attributes["use_default_project"].IsOptional())
+}
+
+func TestResolveProject(t *testing.T) {
+	for name, tc := range map[string]struct {
+		project           types.String
+		envProject        string
+		useDefaultProject types.Bool
+		orgResponse       string
+		orgStatus         int
+		expectedProject   string
+		expectedErr       string
+		expectedOrgReads  int
+	}{
+		"projectAttribute": {
+			project:         types.StringValue("attribute-project"),
+			envProject:      "env-project",
+			expectedProject: "attribute-project",
+		},
+		"projectEnvVar": {
+			project:           types.StringNull(),
+			envProject:        "env-project",
+			useDefaultProject: types.BoolValue(true),
+			expectedProject:   "env-project",
+		},
+		"noProject": {
+			project:     types.StringNull(),
+			expectedErr: "no project specified, set the project attribute or the TFE_PROJECT environment variable, or set use_default_project = true to use the default project of the organization",
+		},
+		"defaultProject": {
+			project:           types.StringNull(),
+			useDefaultProject: types.BoolValue(true),
+			orgResponse: `{"data": {"id": "example-org", "type": "organizations", "attributes": {"name": "example-org"},
+				"relationships": {"default-project": {"data": {"id": "prj-CZcmD7eagjhyX0vN", "type": "projects"}}}},
+				"included": [{"id": "prj-CZcmD7eagjhyX0vN", "type": "projects", "attributes": {"name": "Default Project"}}]}`,
+			expectedProject:  "Default Project",
+			expectedOrgReads: 1,
+		},
+		"noDefaultProject": {
+			project:           types.StringNull(),
+			useDefaultProject: types.BoolValue(true),
+			orgResponse:       `{"data": {"id": "example-org", "type": "organizations", "attributes": {"name": "example-org"}}}`,
+			expectedErr:       "organization example-org has no default project",
+			expectedOrgReads:  1,
+		},
+		"organizationReadFailed": {
+			project:           types.StringNull(),
+			useDefaultProject: types.BoolValue(true),
+			orgStatus:         http.StatusNotFound,
+			expectedErr:       "failed to read organization example-org: resource not found",
+			expectedOrgReads:  1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			ctx := context.Background()
+			orgReads := 0
+			var include string
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("TFP-API-Version", "2.6")
+				if req.URL.Path != "/api/v2/organizations/example-org" {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				orgReads++
+				include = req.URL.Query().Get("include")
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				if tc.orgStatus != 0 {
+					w.WriteHeader(tc.orgStatus)
+					return
+				}
+				_, _ = fmt.Fprint(w, tc.orgResponse)
+			}))
+			defer server.Close()
+			serverUrl, err := url.Parse(server.URL)
+			r.NoError(err)
+			t.Setenv(tfeUtil.TokenEnvNamePrefix+strings.ReplaceAll(serverUrl.Host, ".", "_"), "test-token")
+			t.Setenv(TfeProjectEnvName, tc.envProject)
+			backend := &directoryActions{providerResourceData: ProviderResourceData{
+				Hostname:         serverUrl.Host,
+				TfeClientFactory: NewTfeClientFactory(server.Client()),
+			}}
+			data := DirectoryActionResourceModel{
+				Org:               types.StringValue("example-org"),
+				Project:           tc.project,
+				UseDefaultProject: tc.useDefaultProject,
+				DirectoryPath:     types.StringValue(t.TempDir()),
+				BackendFile:       types.StringValue("backend.tf"),
+				WorkspaceMap:      types.MapValueMust(types.StringType, map[string]attr.Value{"default": types.StringValue("example-workspace")}),
+				Tags:              types.ListValueMust(types.StringType, []attr.Value{}),
+			}
+
+			// Act
+			project, err := backend.resolveProject(ctx, data)
+
+			// Assert
+			r.Equal(tc.expectedOrgReads, orgReads)
+			if tc.expectedOrgReads > 0 {
+				r.Equal("default-project", include)
+			}
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expectedProject, project)
+
+			backendFile := filepath.Join(data.DirectoryPath.ValueString(), data.BackendFile.ValueString())
+			r.NoError(os.WriteFile(backendFile, []byte("terraform {\n}\n"), 0644))
+			resp := &resource.CreateResponse{}
+			AddCloudBlock(ctx, data, project, data.BackendFile.ValueString(), serverUrl.Host, resp)
+			r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			content, err := os.ReadFile(backendFile)
+			r.NoError(err)
+			file, diags := hclwrite.ParseConfig(content, backendFile, hcl.Pos{Line: 1, Column: 1})
+			r.False(diags.HasErrors())
+			workspaces := file.Body().FirstMatchingBlock("terraform", nil).Body().
+				FirstMatchingBlock("cloud", nil).Body().
+				FirstMatchingBlock("workspaces", nil)
+			r.NotNil(workspaces)
+			r.Equal(fmt.Sprintf("%q", tc.expectedProject), strings.TrimSpace(string(workspaces.Body().GetAttribute("project").Expr().BuildTokens(nil).Bytes())))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type workspaceUnlock struct {
+	providerResourceData ProviderResourceData
+}
+
+var (
+	_ resource.Resource = &workspaceUnlock{}
+)
+
+func NewWorkspaceUnlockResource() resource.Resource {
+	return &workspaceUnlock{}
+}
+
+type WorkspaceUnlockModel struct {
+	Org                types.String `tfsdk:"org"`
+	Workspaces         types.List   `tfsdk:"workspaces"`
+	UnlockedWorkspaces types.List   `tfsdk:"unlocked_workspaces"`
+	Summary            types.String `tfsdk:"summary"`
+}
+
+func (r *workspaceUnlock) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_unlock"
+}
+
+func (r *workspaceUnlock) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Workspace Unlock Resource: This resource unlocks the listed workspaces of an organization that are locked by the user owning the TFE token, e.g. after a state migration was interrupted. HCP Terraform does not return the reason a workspace was locked for, so only the listed workspaces are considered. Workspaces locked by other users, teams or runs are left untouched.",
+		Attributes: map[string]schema.Attribute{
+			"org": schema.StringAttribute{
+				MarkdownDescription: "Organization name",
+				Required:            true,
+			},
+			"workspaces": schema.ListAttribute{
+				MarkdownDescription: "Names of the workspaces to unlock, e.g. the `tfc_workspace` of the `tfmigrate_state_migration` resources that were interrupted.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"unlocked_workspaces": schema.ListAttribute{
+				MarkdownDescription: "Names of the workspaces that were unlocked.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"summary": schema.StringAttribute{
+				MarkdownDescription: "Summary of the workspaces unlocked.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *workspaceUnlock) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var data WorkspaceUnlockModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, tfeOp := newTfeOperation(ctx, r.providerResourceData)
+	defer tfeOp.close()
+	defer tfeOp.logApiCalls(ctx)
+	tfeClient, err := newTfeClient(ctx, r.providerResourceData)
+	if err != nil {
+		tflog.Error(ctx, "Error initializing client", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error initializing client ", tfeOp.errorDetail(err))
+		return
+	}
+
+	currentUser, err := tfeClient.Users.ReadCurrent(ctx)
+	if err != nil {
+		tflog.Error(ctx, "Error reading the current user", map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error reading the current user", tfeOp.errorDetail(err))
+		return
+	}
+
+	var workspaceNames []string
+	resp.Diagnostics.Append(data.Workspaces.ElementsAs(ctx, &workspaceNames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	org := data.Org.ValueString()
+	lockedWorkspaces, err := readWorkspacesLockedByUser(ctx, tfeClient, org, workspaceNames, currentUser.ID)
+	if err != nil {
+		tflog.Error(ctx, "Error reading workspaces of organization "+org, map[string]any{"error": err})
+		resp.Diagnostics.AddError("Error reading workspaces of organization "+org, tfeOp.errorDetail(err))
+		return
+	}
+
+	// Each workspace is unlocked separately, so an interrupted or partially failed run can simply be applied again.
+	unlocked := make([]string, 0, len(lockedWorkspaces))
+	for _, workspace := range lockedWorkspaces {
+		if err = ctx.Err(); err != nil {
+			resp.Diagnostics.AddError("Unlocking workspaces was cancelled", fmt.Sprintf("%s\n\nUnlocked workspaces: %v", err.Error(), unlocked))
+			return
+		}
+		tfeOp.startCall()
+		if _, err = tfeClient.Workspaces.Unlock(ctx, workspace.ID); err != nil {
+			tflog.Error(ctx, "Error unlocking workspace "+workspace.Name, map[string]any{"error": err})
+			resp.Diagnostics.AddWarning("Error unlocking workspace "+workspace.Name, tfeOp.errorDetail(err))
+			continue
+		}
+		tflog.Info(ctx, "Unlocked workspace", map[string]any{"workspace": workspace.Name})
+		unlocked = append(unlocked, workspace.Name)
+	}
+
+	unlockedWorkspaces, diags := types.ListValueFrom(ctx, types.StringType, unlocked)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.UnlockedWorkspaces = unlockedWorkspaces
+	data.Summary = types.StringValue(fmt.Sprintf(WorkspaceUnlockSuccess, len(unlocked), len(lockedWorkspaces)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readWorkspacesLockedByUser reads the named workspaces of the organization and returns those locked by the given user.
+func readWorkspacesLockedByUser(ctx context.Context, client *tfe.Client, org string, names []string, userId string) ([]*tfe.Workspace, error) {
+	var lockedWorkspaces []*tfe.Workspace
+	options := &tfe.WorkspaceReadOptions{Include: []tfe.WSIncludeOpt{tfe.WSLockedBy}}
+	for _, name := range names {
+		workspace, err := client.Workspaces.ReadWithOptions(ctx, org, name, options)
+		if err != nil {
+			return nil, fmt.Errorf("reading workspace %s: %w", name, err)
+		}
+		if !isLockedByUser(workspace, userId) {
+			tflog.Info(ctx, "Skipping workspace not locked by the current user", map[string]any{"workspace": name})
+			continue
+		}
+		lockedWorkspaces = append(lockedWorkspaces, workspace)
+	}
+	return lockedWorkspaces, nil
+}
+
+// isLockedByUser reports whether the workspace is locked by the given user.
+func isLockedByUser(workspace *tfe.Workspace, userId string) bool {
+	return workspace.Locked &&
+		workspace.LockedBy != nil &&
+		workspace.LockedBy.User != nil &&
+		workspace.LockedBy.User.ID == userId
+}
+
+func (r *workspaceUnlock) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *workspaceUnlock) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state WorkspaceUnlockModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.UnlockedWorkspaces = state.UnlockedWorkspaces
+	resp.Diagnostics.AddWarning(UpdateActionNotSupported, UpdateActionNotSupportedDetailed)
+	data.Summary = types.StringValue(UpdateActionNotSupported)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *workspaceUnlock) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Warn(ctx, DestroyActionNotSupported)
+}
+
+func (r *workspaceUnlock) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerResourceData, ok := req.ProviderData.(ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Found",
+			fmt.Sprintf("providerResourceData from context is %v.", providerResourceData),
+		)
+		return
+	}
+	r.providerResourceData = providerResourceData
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLockedByUser(t *testing.T) {
+	for name, tc := range map[string]struct {
+		workspace *tfe.Workspace
+		expected  bool
+	}{
+		"notLocked": {
+			workspace: &tfe.Workspace{},
+		},
+		"lockedByCurrentUser": {
+			workspace: &tfe.Workspace{Locked: true, LockedBy: &tfe.LockedByChoice{User: &tfe.User{ID: "user-1"}}},
+			expected:  true,
+		},
+		"lockedByOtherUser": {
+			workspace: &tfe.Workspace{Locked: true, LockedBy: &tfe.LockedByChoice{User: &tfe.User{ID: "user-2"}}},
+		},
+		"lockedByRun": {
+			workspace: &tfe.Workspace{Locked: true, LockedBy: &tfe.LockedByChoice{Run: &tfe.Run{ID: "run-1"}}},
+		},
+		"lockedByUnknown": {
+			workspace: &tfe.Workspace{Locked: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isLockedByUser(tc.workspace, "user-1"))
+		})
+	}
+}
+
+func TestWorkspaceUnlockCreate(t *testing.T) {
+	// Arrange
+	r := require.New(t)
+	ctx := context.Background()
+	// Each workspace is locked by the given user. app-other is locked by the current user for another reason and
+	// is not listed, so it must be left locked.
+	lockedBy := map[string]string{
+		"app-frontend": "user-1",
+		"app-backend":  "user-2",
+		"app-other":    "user-1",
+	}
+	workspaceIds := map[string]string{
+		"app-frontend": "ws-AAAAAAAAAAAAAAA1",
+		"app-backend":  "ws-AAAAAAAAAAAAAAA2",
+		"app-other":    "ws-AAAAAAAAAAAAAAA3",
+	}
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		received = append(received, req.Method+" "+req.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch {
+		case req.URL.Path == "/api/v2/account/details":
+			_, _ = fmt.Fprint(w, `{"data":{"id":"user-1","type":"users","attributes":{"username":"migrator"}}}`)
+		case strings.HasPrefix(req.URL.Path, "/api/v2/organizations/example-org/workspaces/"):
+			name := strings.TrimPrefix(req.URL.Path, "/api/v2/organizations/example-org/workspaces/")
+			_, _ = fmt.Fprintf(w, `{"data":{"id":%q,"type":"workspaces","attributes":{"name":%q,"locked":true},"relationships":{"locked-by":{"data":{"id":%q,"type":"users"}}}},"included":[{"id":%[3]q,"type":"users","attributes":{"username":"someone"}}]}`,
+				workspaceIds[name], name, lockedBy[name])
+		case strings.HasSuffix(req.URL.Path, "/actions/unlock"):
+			_, _ = fmt.Fprintf(w, `{"data":{"id":%q,"type":"workspaces","attributes":{"locked":false}}}`, strings.Split(req.URL.Path, "/")[4])
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	client, err := tfe.NewClient(&tfe.Config{Address: server.URL, Token: "test-token", HTTPClient: server.Client()})
+	r.NoError(err)
+
+	unlock := &workspaceUnlock{providerResourceData: ProviderResourceData{TfeClientFactory: &fakeTfeClientFactory{client: client}}}
+	schemaResp := &resource.SchemaResponse{}
+	unlock.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"org": tftypes.NewValue(tftypes.String, "example-org"),
+		"workspaces": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "app-frontend"),
+			tftypes.NewValue(tftypes.String, "app-backend"),
+		}),
+		"unlocked_workspaces": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+		"summary":             tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+
+	// Act
+	unlock.Create(ctx, resource.CreateRequest{Plan: plan}, resp)
+
+	// Assert
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	var data WorkspaceUnlockModel
+	r.False(resp.State.Get(ctx, &data).HasError())
+	var unlocked []string
+	r.False(data.UnlockedWorkspaces.ElementsAs(ctx, &unlocked, false).HasError())
+	r.Equal([]string{"app-frontend"}, unlocked)
+	r.Equal(fmt.Sprintf(WorkspaceUnlockSuccess, 1, 1), data.Summary.ValueString())
+	r.Contains(received, "POST /api/v2/workspaces/"+workspaceIds["app-frontend"]+"/actions/unlock")
+	for _, request := range received {
+		r.NotContains(request, workspaceIds["app-backend"])
+		r.NotContains(request, "app-other")
+		r.NotContains(request, workspaceIds["app-other"])
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/hashicorp/terraform-exec/tfexec"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -36,6 +37,12 @@ type TerraformPlanSummary struct {
 	Remove int
 }
 
+type TerraformValidateDiagnostic struct {
+	Severity string
+	Summary  string
+	Detail   string
+}
+
 type TerraformOuput struct {
 	Level      string    `json:"@level"`
 	Message    string    `json:"@message"`
@@ -63,6 +70,10 @@ type TerraformOperationInterface interface {
 	ExecuteTerraformInit(ctx context.Context) error
 	SelectWorkspace(ctx context.Context, workspace string) error
 	StatePull(ctx context.Context) ([]byte, error)
+	StatePullWorkspace(ctx context.Context, workspace string) ([]byte, error)
+	StateMv(ctx context.Context, source string, destination string) error
+	StatePush(ctx context.Context, path string) error
+	Validate(ctx context.Context) ([]TerraformValidateDiagnostic, error)
 }
 
 func (tOp *TerraformOperation) ExecuteTerraformPlan(ctx context.Context) (*TerraformPlanSummary, error) {
@@ -143,6 +154,70 @@ func (tOp *TerraformOperation) StatePull(ctx context.Context) ([]byte, error) {
 	return []byte(res), nil
 }
 
+// StatePullWorkspace pulls the state of the given workspace without selecting it, so the workspace selected in
+// the directory is left unchanged. The directory must already be initialized.
+func (tOp *TerraformOperation) StatePullWorkspace(ctx context.Context, workspace string) ([]byte, error) {
+	var buffer, errBuffer bytes.Buffer
+
+	// terraform-exec does not allow setting TF_WORKSPACE, so the command is run directly.
+	cmd := exec.CommandContext(ctx, "terraform", "state", "pull")
+	cmd.Dir = tOp.DirectoryPath
+	cmd.Env = append(os.Environ(), "TF_WORKSPACE="+workspace)
+	cmd.Stdout = &buffer
+	cmd.Stderr = &errBuffer
+	err := cmd.Run()
+
+	if err != nil {
+		return nil, errors.New(errBuffer.String())
+	}
+	return buffer.Bytes(), nil
+}
+
+func (tOp *TerraformOperation) StateMv(ctx context.Context, source string, destination string) error {
+	tf, err := tfexec.NewTerraform(tOp.DirectoryPath, "terraform")
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if mvErr := tf.StateMv(ctx, source, destination); mvErr != nil {
+		return errors.New(mvErr.Error())
+	}
+	return nil
+}
+
+// StatePush pushes the state file at path to the configured backend, holding the state lock while doing so.
+func (tOp *TerraformOperation) StatePush(ctx context.Context, path string) error {
+	tf, err := tfexec.NewTerraform(tOp.DirectoryPath, "terraform")
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	if pushErr := tf.StatePush(ctx, path, tfexec.Lock(true)); pushErr != nil {
+		return errors.New(pushErr.Error())
+	}
+	return nil
+}
+
+// Validate runs terraform validate and returns its diagnostics. The configuration is valid if none of them
+// has the error severity.
+func (tOp *TerraformOperation) Validate(ctx context.Context) ([]TerraformValidateDiagnostic, error) {
+	tf, err := tfexec.NewTerraform(tOp.DirectoryPath, "terraform")
+	if err != nil {
+		return nil, errors.New(err.Error())
+	}
+	output, validateErr := tf.Validate(ctx)
+	if validateErr != nil {
+		return nil, errors.New(validateErr.Error())
+	}
+	diagnostics := make([]TerraformValidateDiagnostic, 0, len(output.Diagnostics))
+	for _, diagnostic := range output.Diagnostics {
+		diagnostics = append(diagnostics, TerraformValidateDiagnostic{
+			Severity: string(diagnostic.Severity),
+			Summary:  diagnostic.Summary,
+			Detail:   diagnostic.Detail,
+		})
+	}
+	return diagnostics, nil
+}
+
 func parseTerraformOutput(buffer bytes.Buffer) []TerraformOuput {
 
 	var terraformOutputs []TerraformOuput
@@ -0,0 +1,317 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RequestIdHeader is the response header holding the ID the server assigned to a request.
+const RequestIdHeader = "X-Request-Id"
+
+// headerRoundTripper sets a fixed set of headers on every request before handing it to the wrapped transport.
+type headerRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+// NewHeaderRoundTripper wraps next so that the given headers are set on every outgoing request.
+// If next is nil, http.DefaultTransport is used.
+func NewHeaderRoundTripper(next http.RoundTripper, headers map[string]string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &headerRoundTripper{
+		headers: headers,
+		next:    next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(h.headers) == 0 {
+		return h.next.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the request it was given.
+	req = req.Clone(req.Context())
+	for name, value := range h.headers {
+		req.Header.Set(name, value)
+	}
+	return h.next.RoundTrip(req)
+}
+
+// maxLoggedPathSegmentLength is the length above which a URL path segment is assumed to be a signed token,
+// e.g. in the upload URLs returned for state versions, and is redacted from debug logs.
+const maxLoggedPathSegmentLength = 64
+
+// debugRoundTripper logs metadata of every request and response passing through the wrapped transport.
+type debugRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewDebugRoundTripper wraps next so that the method, URL, status, duration and request ID of every request are logged.
+// Headers other than request IDs, query strings, long path segments and bodies are never logged as they may hold secrets.
+// If next is nil, http.DefaultTransport is used.
+func NewDebugRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &debugRoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (d *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	fields := map[string]any{
+		"method": req.Method,
+		"url":    SanitizeUrl(req.URL),
+	}
+
+	start := time.Now()
+	resp, err := d.next.RoundTrip(req)
+	fields["duration"] = time.Since(start).String()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Info(ctx, "HTTP request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	for name, values := range resp.Header {
+		if strings.HasSuffix(strings.ToLower(name), "request-id") {
+			fields[name] = strings.Join(values, ",")
+		}
+	}
+	tflog.Info(ctx, "HTTP request completed", fields)
+	return resp, nil
+}
+
+// SanitizeUrl returns the URL without user info and query string, redacting path segments that look like signed tokens.
+func SanitizeUrl(u *url.URL) string {
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, segment := range segments {
+		if len(segment) > maxLoggedPathSegmentLength {
+			segments[i] = "REDACTED"
+		}
+	}
+	return u.Scheme + "://" + u.Host + strings.Join(segments, "/")
+}
+
+// requestIdRecorderKey is the context key of the RequestIdRecorder.
+type requestIdRecorderKey struct{}
+
+// RequestIdRecorder collects the request IDs of failed requests, so they can be included in error messages
+// and used to look up the server side logs.
+type RequestIdRecorder struct {
+	mu         sync.Mutex
+	requestIds []string
+}
+
+// WithRequestIdRecorder returns a context that records the request IDs of failed requests made with it
+// through a transport wrapped by NewRequestIdRoundTripper.
+func WithRequestIdRecorder(ctx context.Context) (context.Context, *RequestIdRecorder) {
+	recorder := &RequestIdRecorder{}
+	return context.WithValue(ctx, requestIdRecorderKey{}, recorder), recorder
+}
+
+// RequestIds returns the recorded request IDs in the order the requests were made.
+func (r *RequestIdRecorder) RequestIds() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.requestIds)
+}
+
+// requestIdRoundTripper records the request ID of every failed response in the RequestIdRecorder of the request context.
+type requestIdRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewRequestIdRoundTripper wraps next so that the request IDs of responses with an error status are recorded
+// in the RequestIdRecorder of the request context, if any.
+// If next is nil, http.DefaultTransport is used.
+func NewRequestIdRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &requestIdRoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *requestIdRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	recorder, ok := req.Context().Value(requestIdRecorderKey{}).(*RequestIdRecorder)
+	if !ok {
+		return resp, err
+	}
+	if requestId := resp.Header.Get(RequestIdHeader); requestId != "" {
+		recorder.mu.Lock()
+		recorder.requestIds = append(recorder.requestIds, requestId)
+		recorder.mu.Unlock()
+	}
+	return resp, err
+}
+
+// defaultContextRoundTripper makes requests created without a context with a default context instead.
+type defaultContextRoundTripper struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// NewDefaultContextRoundTripper wraps next so that requests created without a context, such as the ping request
+// made by tfe.NewClient, are made with ctx and so are tracked by the recorder and counter of ctx.
+// If next is nil, http.DefaultTransport is used.
+func NewDefaultContextRoundTripper(ctx context.Context, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &defaultContextRoundTripper{
+		ctx:  ctx,
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (d *defaultContextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context() == context.Background() {
+		req = req.WithContext(d.ctx)
+	}
+	return d.next.RoundTrip(req)
+}
+
+// ErrApiCallBudgetExceeded is the cause of the context cancellation when an ApiCallCounter exceeds its budget.
+var ErrApiCallBudgetExceeded = errors.New("API call budget exceeded")
+
+// apiCallCounterKey is the context key of the ApiCallCounter.
+type apiCallCounterKey struct{}
+
+// ApiCallCounter counts the requests made per endpoint, and cancels its context once more requests than its
+// budget were made. This stops runaway loops, as the retries of the TFE client end with the context.
+type ApiCallCounter struct {
+	mu       sync.Mutex
+	maxCalls int
+	total    int
+	calls    map[string]int
+	cancel   context.CancelCauseFunc
+}
+
+// WithApiCallCounter returns a context that counts the requests made with it through a transport wrapped by
+// NewApiCallCounterRoundTripper. If maxCalls is 0, the number of requests is not limited.
+func WithApiCallCounter(ctx context.Context, maxCalls int) (context.Context, *ApiCallCounter) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	counter := &ApiCallCounter{
+		maxCalls: maxCalls,
+		calls:    make(map[string]int),
+		cancel:   cancel,
+	}
+	return context.WithValue(ctx, apiCallCounterKey{}, counter), counter
+}
+
+// Total returns the number of requests made.
+func (c *ApiCallCounter) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// Calls returns the number of requests made per endpoint, keyed by method and path with resource IDs and names
+// replaced by placeholders, e.g. "GET /api/v2/workspaces/{id}".
+func (c *ApiCallCounter) Calls() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.calls)
+}
+
+// Exceeded reports whether more requests than the budget were attempted.
+func (c *ApiCallCounter) Exceeded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxCalls > 0 && c.total > c.maxCalls
+}
+
+// Stop releases the context returned by WithApiCallCounter. It must be called once the requests are done.
+func (c *ApiCallCounter) Stop() {
+	c.cancel(context.Canceled)
+}
+
+// WithoutApiCallBudget returns a context that is neither canceled with ctx nor counted by the ApiCallCounter of
+// ctx, for cleanup requests, such as unlocking a workspace, which must be made even once the budget is exceeded.
+func WithoutApiCallBudget(ctx context.Context) context.Context {
+	return context.WithValue(context.WithoutCancel(ctx), apiCallCounterKey{}, (*ApiCallCounter)(nil))
+}
+
+// resourceIdPattern matches the IDs TFE assigns to resources, e.g. ws-CZcmD7eagjhyX0vN.
+var resourceIdPattern = regexp.MustCompile(`^[a-z]+-[A-Za-z0-9]{16}$`)
+
+// endpoint returns the method and path of req with resource IDs and names replaced by placeholders, so requests
+// to the same endpoint are counted together.
+func endpoint(req *http.Request) string {
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, segment := range segments {
+		switch {
+		case len(segment) > maxLoggedPathSegmentLength:
+			segments[i] = "REDACTED"
+		case resourceIdPattern.MatchString(segment):
+			segments[i] = "{id}"
+		case i > 0 && (segments[i-1] == "organizations" || segments[i-1] == "workspaces"):
+			segments[i] = "{name}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// apiCallCounterRoundTripper counts every request in the ApiCallCounter of the request context.
+type apiCallCounterRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewApiCallCounterRoundTripper wraps next so that requests are counted in the ApiCallCounter of the request
+// context, if any. Requests over the budget of the counter fail without being sent. Requests made with a context
+// returned by WithoutApiCallBudget are not counted.
+// If next is nil, http.DefaultTransport is used.
+func NewApiCallCounterRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &apiCallCounterRoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (a *apiCallCounterRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	counter, ok := req.Context().Value(apiCallCounterKey{}).(*ApiCallCounter)
+	if !ok || counter == nil {
+		return a.next.RoundTrip(req)
+	}
+
+	counter.mu.Lock()
+	counter.total++
+	counter.calls[endpoint(req)]++
+	exceeded := counter.maxCalls > 0 && counter.total > counter.maxCalls
+	counter.mu.Unlock()
+	if exceeded {
+		err := fmt.Errorf("%w: more than %d requests were made", ErrApiCallBudgetExceeded, counter.maxCalls)
+		counter.cancel(err)
+		return nil, err
+	}
+	return a.next.RoundTrip(req)
+}
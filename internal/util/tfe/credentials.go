@@ -0,0 +1,223 @@
+package tfeutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// CliConfigFileEnvName is the environment variable Terraform uses to override the CLI config file location.
+	CliConfigFileEnvName = "TF_CLI_CONFIG_FILE"
+	// TokenEnvNamePrefix is the prefix of the host-specific token environment variables, e.g. TF_TOKEN_app_terraform_io.
+	TokenEnvNamePrefix = "TF_TOKEN_"
+
+	credentialsFileName     = "credentials.tfrc.json"
+	credentialsHelperPrefix = "terraform-credentials-"
+)
+
+// cliConfig holds the parts of the Terraform CLI config file relevant for token resolution.
+type cliConfig struct {
+	Credentials       []cliConfigCredentials       `hcl:"credentials,block"`
+	CredentialsHelper []cliConfigCredentialsHelper `hcl:"credentials_helper,block"`
+	Remain            hcl.Body                     `hcl:",remain"`
+}
+
+type cliConfigCredentials struct {
+	Host   string   `hcl:"host,label"`
+	Token  string   `hcl:"token,optional"`
+	Remain hcl.Body `hcl:",remain"`
+}
+
+type cliConfigCredentialsHelper struct {
+	Name string   `hcl:"name,label"`
+	Args []string `hcl:"args,optional"`
+}
+
+// credentialsFile is the format of credentials.tfrc.json, written by terraform login.
+type credentialsFile struct {
+	Credentials map[string]struct {
+		Token string `json:"token"`
+	} `json:"credentials"`
+}
+
+// ReadTfeToken resolves the API token for hostname in the same order as Terraform itself:
+//  1. the TF_TOKEN_<hostname> environment variable,
+//  2. credentials blocks in the CLI config file (TF_CLI_CONFIG_FILE, ~/.terraformrc or %APPDATA%/terraform.rc),
+//  3. credentials.tfrc.json in the Terraform config directory (~/.terraform.d or %APPDATA%/terraform.d),
+//  4. the credentials helper declared in the CLI config file.
+func ReadTfeToken(ctx context.Context, hostname string) (string, error) {
+	hostname = strings.ToLower(hostname)
+
+	if token := os.Getenv(tokenEnvName(hostname)); token != "" {
+		tflog.Debug(ctx, "Using TFE token from environment variable", map[string]any{"name": tokenEnvName(hostname)})
+		return token, nil
+	}
+
+	config, err := readCliConfig()
+	if err != nil {
+		return "", err
+	}
+	for _, creds := range config.Credentials {
+		if strings.ToLower(creds.Host) == hostname && creds.Token != "" {
+			tflog.Debug(ctx, "Using TFE token from CLI config file", map[string]any{"hostname": hostname})
+			return creds.Token, nil
+		}
+	}
+
+	token, err := readCredentialsFile(hostname)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		tflog.Debug(ctx, "Using TFE token from credentials file", map[string]any{"hostname": hostname})
+		return token, nil
+	}
+
+	if len(config.CredentialsHelper) > 0 {
+		helper := config.CredentialsHelper[0]
+		tflog.Debug(ctx, "Using TFE token from credentials helper", map[string]any{"hostname": hostname, "helper": helper.Name})
+		return runCredentialsHelper(ctx, helper, hostname)
+	}
+
+	return "", fmt.Errorf("no credentials found for host %s, run terraform login %s or set the %s environment variable", hostname, hostname, tokenEnvName(hostname))
+}
+
+// tokenEnvName returns the name of the environment variable holding the token for hostname.
+// Dots are replaced with underscores and dashes with double underscores, as done by Terraform.
+func tokenEnvName(hostname string) string {
+	return TokenEnvNamePrefix + strings.NewReplacer(".", "_", "-", "__").Replace(hostname)
+}
+
+// configDir returns the Terraform config directory.
+func configDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "terraform.d"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".terraform.d"), nil
+}
+
+// cliConfigFilePath returns the path of the CLI config file, honouring TF_CLI_CONFIG_FILE.
+func cliConfigFilePath() (string, error) {
+	if path := os.Getenv(CliConfigFileEnvName); path != "" {
+		return path, nil
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "terraform.rc"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".terraformrc"), nil
+}
+
+// readCliConfig reads the credentials related blocks of the CLI config file.
+// A missing CLI config file is not an error.
+func readCliConfig() (*cliConfig, error) {
+	config := &cliConfig{}
+	path, err := cliConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSONFile(path)
+	} else {
+		file, diags = parser.ParseHCLFile(path)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse CLI config file %s: %s", path, diags.Error())
+	}
+	if diags = gohcl.DecodeBody(file.Body, nil, config); diags.HasErrors() {
+		return nil, fmt.Errorf("failed to decode CLI config file %s: %s", path, diags.Error())
+	}
+	return config, nil
+}
+
+// readCredentialsFile reads the token for hostname from credentials.tfrc.json.
+// A missing credentials file is not an error.
+func readCredentialsFile(hostname string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	credsJson, err := os.ReadFile(filepath.Join(dir, credentialsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var creds credentialsFile
+	if err := json.Unmarshal(credsJson, &creds); err != nil {
+		return "", errors.New("failed to parse credential file " + err.Error())
+	}
+	for host, cred := range creds.Credentials {
+		if strings.ToLower(host) == hostname {
+			return cred.Token, nil
+		}
+	}
+	return "", nil
+}
+
+// runCredentialsHelper runs `terraform-credentials-<name> [args...] get <hostname>` and returns the token it prints.
+// The helper is looked up in the plugins directory of the Terraform config directory first and then in PATH.
+func runCredentialsHelper(ctx context.Context, helper cliConfigCredentialsHelper, hostname string) (string, error) {
+	executable := credentialsHelperPrefix + helper.Name
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+
+	helperPath, err := exec.LookPath(executable)
+	if dir, dirErr := configDir(); dirErr == nil {
+		if _, statErr := os.Stat(filepath.Join(dir, "plugins", executable)); statErr == nil {
+			helperPath, err = filepath.Join(dir, "plugins", executable), nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("credentials helper %q not found: %w", helper.Name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, helperPath, append(helper.Args, "get", hostname)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credentials helper %q failed: %w: %s", helper.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("credentials helper %q returned invalid output: %w", helper.Name, err)
+	}
+	if result.Token == "" {
+		return "", fmt.Errorf("credentials helper %q has no credentials for host %s", helper.Name, hostname)
+	}
+	return result.Token, nil
+}
@@ -0,0 +1,125 @@
+package tfeutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testHostname = "tfe.example-corp.com"
+
+func TestReadTfeToken(t *testing.T) {
+	for name, tc := range map[string]struct {
+		envToken        string
+		cliConfig       string
+		credentialsFile string
+		helperScript    string
+		expectedToken   string
+		expectedErr     string
+	}{
+		"noCredentials": {
+			expectedErr: "no credentials found for host tfe.example-corp.com",
+		},
+		"tokenFromEnv": {
+			envToken:        "env-token",
+			cliConfig:       `credentials "tfe.example-corp.com" { token = "cli-config-token" }`,
+			credentialsFile: `{"credentials": {"tfe.example-corp.com": {"token": "credentials-file-token"}}}`,
+			expectedToken:   "env-token",
+		},
+		"tokenFromCliConfig": {
+			cliConfig:       "plugin_cache_dir = \"/tmp\"\ncredentials \"tfe.example-corp.com\" {\n  token = \"cli-config-token\"\n}\n",
+			credentialsFile: `{"credentials": {"tfe.example-corp.com": {"token": "credentials-file-token"}}}`,
+			expectedToken:   "cli-config-token",
+		},
+		"tokenFromCredentialsFile": {
+			cliConfig:       `credentials "app.terraform.io" { token = "other-host-token" }`,
+			credentialsFile: `{"credentials": {"TFE.example-corp.com": {"token": "credentials-file-token"}}}`,
+			expectedToken:   "credentials-file-token",
+		},
+		"invalidCredentialsFile": {
+			credentialsFile: `{"credentials": `,
+			expectedErr:     "failed to parse credential file",
+		},
+		"invalidCliConfig": {
+			cliConfig:   `credentials "tfe.example-corp.com" {`,
+			expectedErr: "failed to parse CLI config file",
+		},
+		"tokenFromCredentialsHelper": {
+			cliConfig:     `credentials_helper "test" { args = ["--flag"] }`,
+			helperScript:  "#!/bin/sh\n[ \"$1 $2 $3\" = \"--flag get tfe.example-corp.com\" ] && echo '{\"token\": \"helper-token\"}'\n",
+			expectedToken: "helper-token",
+		},
+		"credentialsHelperWithoutToken": {
+			cliConfig:    `credentials_helper "test" {}`,
+			helperScript: "#!/bin/sh\necho '{}'\n",
+			expectedErr:  `credentials helper "test" has no credentials for host tfe.example-corp.com`,
+		},
+		"credentialsHelperNotFound": {
+			cliConfig:   `credentials_helper "missing" {}`,
+			expectedErr: `credentials helper "missing" not found`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			if tc.helperScript != "" && runtime.GOOS == "windows" {
+				t.Skip("credentials helper test script requires a POSIX shell")
+			}
+			homeDir := t.TempDir()
+			t.Setenv("HOME", homeDir)
+			t.Setenv("APPDATA", homeDir)
+			t.Setenv("PATH", "")
+			t.Setenv(tokenEnvName(testHostname), tc.envToken)
+			t.Setenv(CliConfigFileEnvName, filepath.Join(homeDir, "cli.tfrc"))
+
+			if tc.cliConfig != "" {
+				r.NoError(os.WriteFile(filepath.Join(homeDir, "cli.tfrc"), []byte(tc.cliConfig), 0600))
+			}
+			dir, err := configDir()
+			r.NoError(err)
+			if tc.credentialsFile != "" {
+				r.NoError(os.MkdirAll(dir, 0700))
+				r.NoError(os.WriteFile(filepath.Join(dir, credentialsFileName), []byte(tc.credentialsFile), 0600))
+			}
+			if tc.helperScript != "" {
+				r.NoError(os.MkdirAll(filepath.Join(dir, "plugins"), 0700))
+				r.NoError(os.WriteFile(filepath.Join(dir, "plugins", credentialsHelperPrefix+"test"), []byte(tc.helperScript), 0700))
+			}
+
+			// Act
+			token, err := ReadTfeToken(context.Background(), testHostname)
+
+			// Assert
+			if tc.expectedErr != "" {
+				r.ErrorContains(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expectedToken, token)
+		})
+	}
+}
+
+func TestTokenEnvName(t *testing.T) {
+	for name, tc := range map[string]struct {
+		hostname string
+		envName  string
+	}{
+		"hcpTerraform": {
+			hostname: "app.terraform.io",
+			envName:  "TF_TOKEN_app_terraform_io",
+		},
+		"hostnameWithDashes": {
+			hostname: "tfe.example-corp.com",
+			envName:  "TF_TOKEN_tfe_example__corp_com",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.envName, tokenEnvName(tc.hostname))
+		})
+	}
+}
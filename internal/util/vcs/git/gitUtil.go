@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
@@ -26,8 +27,6 @@ const (
 	gitlabTokenPrefix            = `glpat-`
 )
 
-var err error
-
 type GitUserConfig struct {
 	Name  string
 	Email string
@@ -64,7 +63,7 @@ type GitUtil interface {
 	CommitObject(repo *git.Repository, hash plumbing.Hash) (*object.Commit, error)
 	ConfigScoped(repo *git.Repository, scope config.Scope) (*config.Config, error)
 	GetGitToken(gitServiceProvider *consts.GitServiceProvider) (string, error)
-	GetOrgAndRepoName(repoIdentifier string) (string, string)
+	GetOrgAndRepoName(repoIdentifier string) (string, string, error)
 	GetRemoteServiceProvider(remoteURL string) *consts.GitServiceProvider
 	GetRepoIdentifier(remoteURL string) string
 	GlobalGitConfig() (GitUserConfig, error)
@@ -88,128 +87,132 @@ func NewGitUtil(ctx context.Context) GitUtil {
 }
 
 func (g *gitUtil) PlainOpenWithOptions(path string, options *git.PlainOpenOptions) (*git.Repository, error) {
-	var repo *git.Repository
-	if repo, err = git.PlainOpenWithOptions(path, options); err != nil {
+	repo, err := git.PlainOpenWithOptions(path, options)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to open repository", map[string]interface{}{"path": path, "error": err})
 	}
 	return repo, err
 }
 
 func (g *gitUtil) Head(repo *git.Repository) (*plumbing.Reference, error) {
-	var head *plumbing.Reference
-	if head, err = repo.Head(); err != nil {
+	head, err := repo.Head()
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get repository head", map[string]interface{}{"error": err})
 	}
 	return head, err
 }
 
 func (g *gitUtil) Worktree(repo *git.Repository) (*git.Worktree, error) {
-	var worktree *git.Worktree
-	if worktree, err = repo.Worktree(); err != nil {
+	worktree, err := repo.Worktree()
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get repository worktree", map[string]interface{}{"error": err})
 	}
 	return worktree, err
 }
 
 func (g *gitUtil) Reset(worktree *git.Worktree, options *git.ResetOptions) error {
-	if err = worktree.Reset(options); err != nil {
+	err := worktree.Reset(options)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to reset worktree", map[string]interface{}{"options": options, "error": err})
 	}
 	return err
 }
 
 func (g *gitUtil) CommitObject(repo *git.Repository, hash plumbing.Hash) (*object.Commit, error) {
-	var commit *object.Commit
-	if commit, err = repo.CommitObject(hash); err != nil {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get commit object", map[string]interface{}{"hash": hash, "error": err})
 	}
 	return commit, err
 }
 
 func (g *gitUtil) Branches(repo *git.Repository) (storer.ReferenceIter, error) {
-	var branches storer.ReferenceIter
-	if branches, err = repo.Branches(); err != nil {
+	branches, err := repo.Branches()
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get repository branches", map[string]interface{}{"error": err})
 	}
 	return branches, err
 }
 
 func (g *gitUtil) Checkout(worktree *git.Worktree, options *git.CheckoutOptions) error {
-	if err = worktree.Checkout(options); err != nil {
+	err := worktree.Checkout(options)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to checkout worktree", map[string]interface{}{"options": options, "error": err})
 	}
 	return err
 }
 
 func (g *gitUtil) RemoveReference(storer storer.ReferenceStorer, ref plumbing.ReferenceName) error {
-	if err = storer.RemoveReference(ref); err != nil {
+	err := storer.RemoveReference(ref)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to remove reference", map[string]interface{}{"ref": ref, "error": err})
 	}
 	return err
 }
 
 func (g *gitUtil) Add(worktree *git.Worktree, glob string) (plumbing.Hash, error) {
-	var hash plumbing.Hash
-	if hash, err = worktree.Add(glob); err != nil {
+	hash, err := worktree.Add(glob)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to add to worktree", map[string]interface{}{"glob": glob, "error": err})
 	}
 	return hash, err
 }
 
 func (g *gitUtil) Commit(worktree *git.Worktree, msg string, options *git.CommitOptions) (plumbing.Hash, error) {
-	var hash plumbing.Hash
-	if hash, err = worktree.Commit(msg, options); err != nil {
+	hash, err := worktree.Commit(msg, options)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to commit worktree", map[string]interface{}{"message": msg, "options": options, "error": err})
 	}
 	return hash, err
 }
 
 func (g *gitUtil) Status(worktree *git.Worktree) (git.Status, error) {
-	var status git.Status
-	if status, err = worktree.Status(); err != nil {
+	status, err := worktree.Status()
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get worktree status", map[string]interface{}{"error": err})
 	}
 	return status, err
 }
 
 func (g *gitUtil) Push(repo *git.Repository, o *git.PushOptions) error {
-	if err = repo.Push(o); err != nil {
+	err := repo.Push(o)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to push to repository", map[string]interface{}{"options": o, "error": err})
 	}
 	return err
 }
 
 func (g *gitUtil) Remotes(repo *git.Repository) ([]*git.Remote, error) {
-	var remotes []*git.Remote
-	if remotes, err = repo.Remotes(); err != nil {
+	remotes, err := repo.Remotes()
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get repository remotes", map[string]interface{}{"error": err})
 	}
 	return remotes, err
 }
 
 func (g *gitUtil) ConfigScoped(repo *git.Repository, scope config.Scope) (*config.Config, error) {
-	var configSc *config.Config
-	if configSc, err = repo.ConfigScoped(scope); err != nil {
+	configSc, err := repo.ConfigScoped(scope)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to get scoped config", map[string]interface{}{"scope": scope, "error": err})
 	}
 	return configSc, err
 }
 
 func (g *gitUtil) NewGitLabClient(gitlabToken string) (*gitlab.Client, error) {
-	var gitLabNewClient *gitlab.Client
-	if gitLabNewClient, err = gitlab.NewClient(gitlabToken); err != nil {
+	gitLabNewClient, err := gitlab.NewClient(gitlabToken)
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to create GitLab client", map[string]interface{}{"error": err})
 	}
 	return gitLabNewClient, err
 }
 
 func (g *gitUtil) GlobalGitConfig() (GitUserConfig, error) {
-	var repo *git.Repository
-	var cfg *config.Config
-	if repo, err = g.OpenRepository("."); err != nil {
+	repo, err := g.OpenRepository(".")
+	if err != nil {
 		return GitUserConfig{}, err
 	}
-	if cfg, err = g.ConfigScoped(repo, config.GlobalScope); err != nil {
+	cfg, err := g.ConfigScoped(repo, config.GlobalScope)
+	if err != nil {
 		return GitUserConfig{}, err
 	}
 	return GitUserConfig{
@@ -219,10 +222,10 @@ func (g *gitUtil) GlobalGitConfig() (GitUserConfig, error) {
 }
 
 func (g *gitUtil) OpenRepository(repoPath string) (*git.Repository, error) {
-	var repo *git.Repository
-	if repo, err = g.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{
+	repo, err := g.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{
 		DetectDotGit: true,
-	}); err != nil {
+	})
+	if err != nil {
 		tflog.Error(context.Background(), "Failed to open repository", map[string]interface{}{"repoPath": repoPath, "error": err})
 	}
 	return repo, err
@@ -284,47 +287,89 @@ func (g *gitUtil) getGitlabPatToken(gitPatToken string) (string, error) {
 // GetRepoIdentifier gets the repo identifier.
 // In case of GitHub, the repo identifier is in the format "owner/repo".
 // In case of GitLab, the repo identifier is in the format "group/repo".
+// An empty string is returned if the remote URL cannot be parsed or is not hosted on a supported service provider.
 func (g *gitUtil) GetRepoIdentifier(remoteURL string) string {
-
-	var repoIdentifier string
-	gitSvcProvider := g.GetRemoteServiceProvider(remoteURL)
-	switch *gitSvcProvider {
-	case consts.GitHub:
-		repoIdentifier = g.getRepoIdentifierFromRemoteURl(remoteURL, consts.GitHub)
-	case consts.GitLab:
-		repoIdentifier = g.getRepoIdentifierFromRemoteURl(remoteURL, consts.GitLab)
-	default:
+	host, repoPath, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		tflog.Error(g.ctx, "Failed to parse git remote URL", map[string]interface{}{"error": err})
 		return ""
 	}
-	return strings.TrimSpace(repoIdentifier)
-}
-
-// getRepoIdentifierFromRemoteURl gets the repo identifier from the remote URL.
-func (g *gitUtil) getRepoIdentifierFromRemoteURl(remoteURL string, gitSvcProvider consts.GitServiceProvider) string {
-	var repoIdentifier string
-	if strings.HasPrefix(remoteURL, "git@") {
-		repoIdentifier = strings.Split(remoteURL, string(gitSvcProvider)+":")[1]
-	} else {
-		repoIdentifier = strings.Split(remoteURL, string(gitSvcProvider)+"/")[1]
+	if getServiceProviderFromHost(host) == consts.UnknownGitServiceProvider {
+		return ""
+	}
+	if !strings.Contains(repoPath, "/") {
+		tflog.Error(g.ctx, "Failed to get repo identifier from git remote URL", map[string]interface{}{"error": cliErrs.ErrRepoIdentifierInvalid})
+		return ""
 	}
-	return strings.TrimSuffix(repoIdentifier, ".git")
+	return repoPath
 }
 
 // GetOrgAndRepoName gets the org and repo name.
-func (g *gitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string) {
-	orgAndRepo := strings.Split(repoIdentifier, "/")
-	return orgAndRepo[0], orgAndRepo[1]
+// For GitLab projects in subgroups, the org name is the full group path.
+func (g *gitUtil) GetOrgAndRepoName(repoIdentifier string) (string, string, error) {
+	idx := strings.LastIndex(repoIdentifier, "/")
+	if idx <= 0 || idx == len(repoIdentifier)-1 {
+		return "", "", cliErrs.ErrRepoIdentifierInvalid
+	}
+	return repoIdentifier[:idx], repoIdentifier[idx+1:], nil
 }
 
 // GetRemoteServiceProvider gets the remote service provider.
 func (g *gitUtil) GetRemoteServiceProvider(remoteURL string) *consts.GitServiceProvider {
-	if strings.Contains(remoteURL, string(consts.GitHub)) {
-		return &consts.GitHub
+	host, _, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return &consts.UnknownGitServiceProvider
 	}
-	if strings.Contains(remoteURL, string(consts.GitLab)) {
+	switch getServiceProviderFromHost(host) {
+	case consts.GitHub:
+		return &consts.GitHub
+	case consts.GitLab:
 		return &consts.GitLab
+	default:
+		return &consts.UnknownGitServiceProvider
+	}
+}
+
+// getServiceProviderFromHost returns the service provider for the host of a remote URL.
+// Subdomains such as ssh.github.com, used for SSH over the HTTPS port, are matched as well.
+func getServiceProviderFromHost(host string) consts.GitServiceProvider {
+	for _, svcProvider := range []consts.GitServiceProvider{consts.GitHub, consts.GitLab} {
+		if host == string(svcProvider) || strings.HasSuffix(host, "."+string(svcProvider)) {
+			return svcProvider
+		}
+	}
+	return consts.UnknownGitServiceProvider
+}
+
+// parseRemoteURL splits a git remote URL into its host and repository path, without the .git suffix.
+// Both URL syntax, e.g. https://github.com/owner/repo.git or ssh://git@github.com:22/owner/repo.git,
+// and the scp-like syntax, e.g. git@github.com:owner/repo.git, are supported.
+func parseRemoteURL(remoteURL string) (string, string, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var host, repoPath string
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", "", cliErrs.ErrRemoteUrlInvalid
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		userAndHost, path, found := strings.Cut(remoteURL, ":")
+		if !found {
+			return "", "", cliErrs.ErrRemoteUrlInvalid
+		}
+		if _, hostAfterUser, hasUser := strings.Cut(userAndHost, "@"); hasUser {
+			userAndHost = hostAfterUser
+		}
+		host, repoPath = userAndHost, path
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return "", "", cliErrs.ErrRemoteUrlInvalid
 	}
-	return &consts.UnknownGitServiceProvider
+	return strings.ToLower(host), repoPath, nil
 }
 
 // getTokenType returns the type of GitHub token.
@@ -127,6 +127,30 @@ func TestGetRepoIdentifier(t *testing.T) {
 			repoIdentifier: "hashicorp/terraform-provider-aws",
 			repoUrl:        "https://gitlab.com/hashicorp/terraform-provider-aws.git",
 		},
+		"githubSshSchemeRepoUrl": {
+			repoIdentifier: "hashicorp/terraform-provider-aws",
+			repoUrl:        "ssh://git@github.com:22/hashicorp/terraform-provider-aws.git",
+		},
+		"githubHttpRepoUrlWithoutGitSuffix": {
+			repoIdentifier: "hashicorp/terraform-provider-aws",
+			repoUrl:        "https://github.com/hashicorp/terraform-provider-aws/",
+		},
+		"gitlabSubgroupRepoUrl": {
+			repoIdentifier: "hashicorp/infra/terraform-provider-aws",
+			repoUrl:        "git@gitlab.com:hashicorp/infra/terraform-provider-aws.git",
+		},
+		"repoUrlWithoutPath": {
+			repoIdentifier: "",
+			repoUrl:        "https://github.com",
+		},
+		"repoUrlWithoutRepoName": {
+			repoIdentifier: "",
+			repoUrl:        "https://github.com/hashicorp",
+		},
+		"invalidRepoUrl": {
+			repoIdentifier: "",
+			repoUrl:        "github.com",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			// Arrange
@@ -149,12 +173,26 @@ func TestGetOrgAndRepoName(t *testing.T) {
 		orgName  string
 		repoName string
 		repoId   string
+		err      error
 	}{
 		"validRepoId": {
 			orgName:  "hashicorp",
 			repoName: "terraform-provider-aws",
 			repoId:   "hashicorp/terraform-provider-aws",
 		},
+		"subgroupRepoId": {
+			orgName:  "hashicorp/infra",
+			repoName: "terraform-provider-aws",
+			repoId:   "hashicorp/infra/terraform-provider-aws",
+		},
+		"repoIdWithoutOrg": {
+			repoId: "terraform-provider-aws",
+			err:    cliErrs.ErrRepoIdentifierInvalid,
+		},
+		"repoIdWithoutRepoName": {
+			repoId: "hashicorp/",
+			err:    cliErrs.ErrRepoIdentifierInvalid,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			// Arrange
@@ -162,9 +200,10 @@ func TestGetOrgAndRepoName(t *testing.T) {
 			ctx := context.Background()
 			gitOps := NewGitUtil(ctx)
 			// Act
-			orgName, repoName := gitOps.GetOrgAndRepoName(tc.repoId)
+			orgName, repoName, err := gitOps.GetOrgAndRepoName(tc.repoId)
 
 			// Assert
+			r.Equal(tc.err, err)
 			r.Equal(orgName, tc.orgName)
 			r.Equal(repoName, tc.repoName)
 		})
@@ -189,6 +228,18 @@ func TestGetRemoteServiceProvider(t *testing.T) {
 			gitSvcPvd: &consts.GitLab,
 			repoUrl:   "https://gitlab.com/hashicorp/terraform-provider-aws.git",
 		},
+		"githubSshSchemeRepoUrl": {
+			gitSvcPvd: &consts.GitHub,
+			repoUrl:   "ssh://git@ssh.github.com:443/hashicorp/terraform-provider-aws.git",
+		},
+		"githubInPathOnly": {
+			gitSvcPvd: &consts.UnknownGitServiceProvider,
+			repoUrl:   "https://unknown.com/github.com/terraform-provider-aws.git",
+		},
+		"invalidRepoUrl": {
+			gitSvcPvd: &consts.UnknownGitServiceProvider,
+			repoUrl:   "https://",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			// Arrange
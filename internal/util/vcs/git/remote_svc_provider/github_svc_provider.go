@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"terraform-provider-tfmigrate/internal/util/vcs/git"
 
@@ -34,7 +33,10 @@ func (g *githubSvcProvider) ValidateToken(repoUrl string, repoIdentifier string)
 		return suggestions, gitTokenErr
 	}
 
-	orgName, repoName := g.git.GetOrgAndRepoName(repoIdentifier)
+	orgName, repoName, err := g.git.GetOrgAndRepoName(repoIdentifier)
+	if err != nil {
+		return "", err
+	}
 	if statusCode, err := g.validateGithubTokenRepoAccess(orgName, repoName); err != nil {
 		return gitTokenErrorHandler(err, statusCode)
 	}
@@ -42,6 +44,23 @@ func (g *githubSvcProvider) ValidateToken(repoUrl string, repoIdentifier string)
 	return "", nil
 }
 
+// GetDefaultBranch returns the default branch of the github repository.
+func (g *githubSvcProvider) GetDefaultBranch(repoIdentifier string) (string, error) {
+	orgName, repoName, err := g.git.GetOrgAndRepoName(repoIdentifier)
+	if err != nil {
+		return "", err
+	}
+
+	repoDetails, _, err := g.githubUtil.GetRepository(orgName, repoName)
+	if err != nil {
+		return "", err
+	}
+	if repoDetails.GetDefaultBranch() == "" {
+		return "", cliErrs.ErrDefaultBranchNotFound
+	}
+	return repoDetails.GetDefaultBranch(), nil
+}
+
 // validateGithubTokenRepoAccess validates the github pat token.
 func (g *githubSvcProvider) validateGithubTokenRepoAccess(owner string, repositoryName string) (int, error) {
 
@@ -94,8 +113,11 @@ func (g *githubSvcProvider) CreatePullRequest(params git.PullRequestParams) (str
 		Body:  github.String(params.Body),
 	}
 
-	repoOwner := strings.Split(params.RepoIdentifier, "/")[0]
-	repoName := strings.Split(params.RepoIdentifier, "/")[1]
+	repoOwner, repoName, err := g.git.GetOrgAndRepoName(params.RepoIdentifier)
+	if err != nil {
+		tflog.Error(g.ctx, "Failed to create pull request", map[string]interface{}{"error": err})
+		return "", err
+	}
 
 	if pr, resp, err = client.PullRequests.Create(g.ctx, repoOwner, repoName, newPR); err != nil {
 		tflog.Error(g.ctx, "Failed to create pull request", map[string]interface{}{"owner": repoOwner, "repo": repoName, "pull": newPR.GetTitle(), "error": err})
@@ -235,7 +235,7 @@ func TestValidateToken(t *testing.T) {
 
 				git.
 					On("GetOrgAndRepoName", mock.Anything).
-					Return("hashicorp", "tf-migrate")
+					Return("hashicorp", "tf-migrate", nil)
 
 				if name == "UnknownErrorOccurred" {
 					githubUtil.
@@ -338,3 +338,46 @@ func getMockResponse(statusCode int, body string) *http.Response {
 		},
 	}
 }
+
+func TestGetDefaultBranch(t *testing.T) {
+	for name, tc := range map[string]struct {
+		repoDetails   *github.Repository
+		apiErr        error
+		defaultBranch string
+		err           error
+	}{
+		"Success": {
+			repoDetails:   &github.Repository{DefaultBranch: github.String("main")},
+			defaultBranch: "main",
+		},
+		"ApiError": {
+			apiErr: cliErrs.ErrRepositoryNotFound,
+			err:    cliErrs.ErrRepositoryNotFound,
+		},
+		"DefaultBranchNotSet": {
+			repoDetails: &github.Repository{},
+			err:         cliErrs.ErrDefaultBranchNotFound,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			git := gitMocks.NewMockGitUtil(t)
+			githubUtil := gitMocks.NewMockGithubUtil(t)
+			g := &githubSvcProvider{
+				ctx:        context.Background(),
+				git:        git,
+				githubUtil: githubUtil,
+			}
+			git.On("GetOrgAndRepoName", "hashicorp/tf-migrate").Return("hashicorp", "tf-migrate", nil)
+			githubUtil.On("GetRepository", "hashicorp", "tf-migrate").Return(tc.repoDetails, nil, tc.apiErr)
+
+			// Act
+			defaultBranch, err := g.GetDefaultBranch("hashicorp/tf-migrate")
+
+			// Assert
+			r.Equal(tc.err, err)
+			r.Equal(tc.defaultBranch, defaultBranch)
+		})
+	}
+}
@@ -39,6 +39,18 @@ func (g *gitlabSvcProvider) ValidateToken(repoUrl string, projectIdentifier stri
 	return "", nil
 }
 
+// GetDefaultBranch returns the default branch of the GitLab project.
+func (g *gitlabSvcProvider) GetDefaultBranch(projectIdentifier string) (string, error) {
+	projectDetails, _, err := g.gitlabUtil.GetProject(projectIdentifier)
+	if err != nil {
+		return "", err
+	}
+	if projectDetails == nil || projectDetails.DefaultBranch == "" {
+		return "", cliErrs.ErrDefaultBranchNotFound
+	}
+	return projectDetails.DefaultBranch, nil
+}
+
 // validateGitlabTokenRepoAccess validates the GitLab PAT token for repository access.
 func (g *gitlabSvcProvider) validateGitlabTokenRepoAccess(projectIdentifier string) (int, error) {
 	projectDetails, resp, err := g.gitlabUtil.GetProject(projectIdentifier)
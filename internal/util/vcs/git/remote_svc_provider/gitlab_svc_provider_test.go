@@ -140,3 +140,44 @@ func getMockGitlabResponse(statusCode int, body string) *gitlab.Response {
 		},
 	}
 }
+
+func TestGetDefaultBranch_gitlab(t *testing.T) {
+	for name, tc := range map[string]struct {
+		projectDetails *gitlab.Project
+		apiErr         error
+		defaultBranch  string
+		err            error
+	}{
+		"Success": {
+			projectDetails: &gitlab.Project{DefaultBranch: "main"},
+			defaultBranch:  "main",
+		},
+		"ApiError": {
+			apiErr: cliErrs.ErrRepositoryNotFound,
+			err:    cliErrs.ErrRepositoryNotFound,
+		},
+		"DefaultBranchNotSet": {
+			projectDetails: &gitlab.Project{},
+			err:            cliErrs.ErrDefaultBranchNotFound,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := require.New(t)
+			gitlabUtil := gitMocks.NewMockGitlabUtil(t)
+			g := &gitlabSvcProvider{
+				ctx:        context.Background(),
+				git:        gitMocks.NewMockGitUtil(t),
+				gitlabUtil: gitlabUtil,
+			}
+			gitlabUtil.On("GetProject", "demo-group/sample-project").Return(tc.projectDetails, nil, tc.apiErr)
+
+			// Act
+			defaultBranch, err := g.GetDefaultBranch("demo-group/sample-project")
+
+			// Assert
+			r.Equal(tc.err, err)
+			r.Equal(tc.defaultBranch, defaultBranch)
+		})
+	}
+}
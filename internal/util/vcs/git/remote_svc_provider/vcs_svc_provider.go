@@ -19,6 +19,7 @@ type remoteSvcProviderFactory struct {
 type RemoteVcsSvcProvider interface {
 	ValidateToken(repoUrl string, repoIdentifier string) (string, error)
 	CreatePullRequest(params git.PullRequestParams) (string, error)
+	GetDefaultBranch(repoIdentifier string) (string, error)
 }
 
 // RemoteVcsSvcProviderFactory is the factory interface for creating RemoteVcsSvcProvider.